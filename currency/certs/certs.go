@@ -0,0 +1,197 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc/credentials"
+)
+
+// Files is a certificate and key pair and a CA bundle loaded from disk. The
+// files are checked on every TLS handshake and reloaded when they have changed,
+// this allows certificates to be rotated without restarting the service
+type Files struct {
+	certFile string
+	keyFile  string
+	caFile   string
+	log      hclog.Logger
+
+	// mu guards the fields below
+	mu       sync.Mutex
+	cert     *tls.Certificate
+	pool     *x509.CertPool
+	modified time.Time // latest modification time of the loaded files
+}
+
+// Load loads the certificate and key pair and the CA bundle from the given files.
+// The certificate and key or the CA file can be empty when they are not required,
+// e.g. a client which does not use mutual TLS only needs a CA file
+func Load(certFile, keyFile, caFile string, l hclog.Logger) (*Files, error) {
+	if (certFile == "") != (keyFile == "") {
+		return nil, fmt.Errorf("both a certificate and key file are required")
+	}
+
+	f := &Files{certFile: certFile, keyFile: keyFile, caFile: caFile, log: l}
+
+	m, err := f.modTime()
+	if err != nil {
+		return nil, err
+	}
+
+	err = f.load(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// ServerConfig returns the TLS configuration for a server, when a CA file has
+// been loaded clients must present a certificate signed by one of the CAs
+func (f *Files) ServerConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, pool := f.current()
+			if cert == nil {
+				return nil, fmt.Errorf("no server certificate configured")
+			}
+
+			c := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+				// the config replaces the one set up by gRPC so ALPN must be configured here
+				NextProtos: []string{"h2"},
+			}
+
+			if pool != nil {
+				c.ClientCAs = pool
+				c.ClientAuth = tls.RequireAndVerifyClientCert
+			}
+
+			return c, nil
+		},
+	}
+}
+
+// ClientConfig returns the TLS configuration for a client. The server certificate
+// is verified with the loaded CAs or the system roots when no CA file was given,
+// the certificate is presented to servers which request a client certificate
+func (f *Files) ClientConfig() *tls.Config {
+	cert, pool := f.current()
+
+	c := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    pool,
+	}
+
+	if cert != nil {
+		c.Certificates = []tls.Certificate{*cert}
+	}
+
+	return c
+}
+
+// ClientCredentials returns gRPC transport credentials for a client which use
+// the current files for every new connection
+func (f *Files) ClientCredentials() credentials.TransportCredentials {
+	return &clientCredentials{files: f, TransportCredentials: credentials.NewTLS(f.ClientConfig())}
+}
+
+// current returns the certificate and CA pool, reloading them if the files have changed.
+// A failed reload is logged and the previously loaded files continue to be used
+func (f *Files) current() (*tls.Certificate, *x509.CertPool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	m, err := f.modTime()
+	if err == nil && m.After(f.modified) {
+		err = f.load(m)
+		if err == nil {
+			f.log.Info("Reloaded TLS files", "cert", f.certFile, "ca", f.caFile)
+		}
+	}
+
+	if err != nil {
+		f.log.Error("Unable to reload TLS files", "error", err)
+	}
+
+	return f.cert, f.pool
+}
+
+// load reads the files, the files are only replaced when they are all valid
+func (f *Files) load(modified time.Time) error {
+	var cert *tls.Certificate
+	if f.certFile != "" {
+		c, err := tls.LoadX509KeyPair(f.certFile, f.keyFile)
+		if err != nil {
+			return fmt.Errorf("unable to load certificate: %w", err)
+		}
+
+		cert = &c
+	}
+
+	var pool *x509.CertPool
+	if f.caFile != "" {
+		pem, err := os.ReadFile(f.caFile)
+		if err != nil {
+			return fmt.Errorf("unable to read CA file: %w", err)
+		}
+
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in CA file %s", f.caFile)
+		}
+	}
+
+	f.cert = cert
+	f.pool = pool
+	f.modified = modified
+
+	return nil
+}
+
+// modTime returns the latest modification time of the files
+func (f *Files) modTime() (time.Time, error) {
+	var m time.Time
+
+	for _, p := range []string{f.certFile, f.keyFile, f.caFile} {
+		if p == "" {
+			continue
+		}
+
+		fi, err := os.Stat(p)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		if fi.ModTime().After(m) {
+			m = fi.ModTime()
+		}
+	}
+
+	return m, nil
+}
+
+// clientCredentials creates the TLS configuration from the current files for
+// every handshake, the CA pool used to verify servers can not be changed in
+// a static configuration
+type clientCredentials struct {
+	files *Files
+	credentials.TransportCredentials
+}
+
+func (c *clientCredentials) ClientHandshake(ctx context.Context, authority string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return credentials.NewTLS(c.files.ClientConfig()).ClientHandshake(ctx, authority, conn)
+}
+
+func (c *clientCredentials) Clone() credentials.TransportCredentials {
+	return &clientCredentials{files: c.files, TransportCredentials: c.TransportCredentials.Clone()}
+}
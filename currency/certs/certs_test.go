@@ -0,0 +1,240 @@
+package certs
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// testCA is a certificate authority which issues certificates for tests
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, _ := x509.ParseCertificate(der)
+
+	return &testCA{cert, key, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a PEM encoded certificate and key for localhost
+func (ca *testCA) issue(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kd, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: kd})
+}
+
+// writeFile writes the file with a modification time after any previous write
+func writeFile(t *testing.T, path string, data []byte, modified time.Time) {
+	err := os.WriteFile(path, data, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.Chtimes(path, modified, modified)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// serve starts a gRPC server with the health service using the credentials
+func serve(t *testing.T, creds credentials.TransportCredentials) string {
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gs := grpc.NewServer(grpc.Creds(creds))
+	healthpb.RegisterHealthServer(gs, health.NewServer())
+	go gs.Serve(l)
+	t.Cleanup(gs.Stop)
+
+	return l.Addr().String()
+}
+
+// check calls the health service, the call fails when the TLS handshake fails
+func check(addr string, creds credentials.TransportCredentials) error {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+
+	return err
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	p := func(n string) string { return filepath.Join(dir, n) }
+	now := time.Now()
+
+	ca := newTestCA(t)
+	sc, sk := ca.issue(t)
+	cc, ck := ca.issue(t)
+
+	writeFile(t, p("ca.pem"), ca.pem, now)
+	writeFile(t, p("server.pem"), sc, now)
+	writeFile(t, p("server-key.pem"), sk, now)
+	writeFile(t, p("client.pem"), cc, now)
+	writeFile(t, p("client-key.pem"), ck, now)
+
+	sf, err := Load(p("server.pem"), p("server-key.pem"), p("ca.pem"), hclog.NewNullLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr := serve(t, credentials.NewTLS(sf.ServerConfig()))
+
+	cf, err := Load(p("client.pem"), p("client-key.pem"), p("ca.pem"), hclog.NewNullLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = check(addr, cf.ClientCredentials())
+	if err != nil {
+		t.Fatalf("expected client with certificate to connect, got %s", err)
+	}
+
+	// clients which only verify the server are rejected
+	nf, err := Load("", "", p("ca.pem"), hclog.NewNullLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = check(addr, nf.ClientCredentials())
+	if err == nil {
+		t.Fatal("expected client without certificate to be rejected")
+	}
+}
+
+func TestReloadsChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	p := func(n string) string { return filepath.Join(dir, n) }
+	now := time.Now()
+
+	ca := newTestCA(t)
+	sc, sk := ca.issue(t)
+
+	writeFile(t, p("ca.pem"), ca.pem, now)
+	writeFile(t, p("server.pem"), sc, now)
+	writeFile(t, p("server-key.pem"), sk, now)
+
+	sf, err := Load(p("server.pem"), p("server-key.pem"), "", hclog.NewNullLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cf, err := Load("", "", p("ca.pem"), hclog.NewNullLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr := serve(t, credentials.NewTLS(sf.ServerConfig()))
+
+	err = check(addr, cf.ClientCredentials())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// rotate to a certificate from a new CA on both sides
+	rotated := newTestCA(t)
+	sc, sk = rotated.issue(t)
+	later := now.Add(time.Minute)
+
+	writeFile(t, p("ca.pem"), rotated.pem, later)
+	writeFile(t, p("server.pem"), sc, later)
+	writeFile(t, p("server-key.pem"), sk, later)
+
+	err = check(addr, cf.ClientCredentials())
+	if err != nil {
+		t.Fatalf("expected reloaded files to be used, got %s", err)
+	}
+
+	// a client which still trusts the old CA is rejected
+	writeFile(t, p("old-ca.pem"), ca.pem, later)
+
+	of, err := Load("", "", p("old-ca.pem"), hclog.NewNullLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = check(addr, of.ClientCredentials())
+	if err == nil {
+		t.Fatal("expected client trusting the old CA to be rejected")
+	}
+}
+
+func TestLoadRequiresCertificateAndKey(t *testing.T) {
+	_, err := Load("cert.pem", "", "", hclog.NewNullLogger())
+	if err == nil {
+		t.Fatal("expected error when the key file is missing")
+	}
+}
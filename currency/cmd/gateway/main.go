@@ -0,0 +1,103 @@
+// Command gateway serves the currency service as HTTP and JSON, it runs as a
+// sidecar next to the currency service
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	gohandlers "github.com/gorilla/handlers"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hnsia/go-nic/currency/certs"
+	"github.com/hnsia/go-nic/currency/gateway"
+	protos "github.com/hnsia/go-nic/currency/protos/currency/currency"
+	"github.com/nicholasjackson/env"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+var bindAddress = env.String("BIND_ADDRESS", false, ":9094", "Bind address for the HTTP server")
+var logLevel = env.String("LOG_LEVEL", false, "info", "Log output level for the server [trace, debug, info, warn, error]")
+var allowedOrigins = env.String("ALLOWED_ORIGINS", false, "*", "Comma separated origins allowed to call the gateway from a browser")
+var currencyAddress = env.String("CURRENCY_ADDRESS", false, "localhost:9092", "Address of the currency service")
+var currencyTLS = env.Bool("CURRENCY_TLS", false, false, "Connect to the currency service using TLS")
+var currencyCA = env.String("CURRENCY_TLS_CA_FILE", false, "", "Path to a PEM encoded CA bundle used to verify the currency service, the system roots are used when empty")
+var currencyCert = env.String("CURRENCY_TLS_CERT_FILE", false, "", "Path to a PEM encoded client certificate presented to the currency service for mutual TLS")
+var currencyKey = env.String("CURRENCY_TLS_KEY_FILE", false, "", "Path to the PEM encoded private key for the client certificate")
+
+func main() {
+	err := env.Parse()
+	if err != nil {
+		hclog.Default().Error("Unable to parse configuration", "error", err)
+		os.Exit(1)
+	}
+
+	l := hclog.New(&hclog.LoggerOptions{
+		Name:  "currency-gateway",
+		Level: hclog.LevelFromString(*logLevel),
+	})
+
+	var creds credentials.TransportCredentials = insecure.NewCredentials()
+	if *currencyTLS {
+		// the files are reloaded when they change
+		tf, err := certs.Load(*currencyCert, *currencyKey, *currencyCA, l)
+		if err != nil {
+			l.Error("Unable to load TLS files", "error", err)
+			os.Exit(1)
+		}
+
+		creds = tf.ClientCredentials()
+	}
+
+	conn, err := grpc.NewClient(*currencyAddress, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		l.Error("Unable to create currency client", "error", err)
+		os.Exit(1)
+	}
+	defer conn.Close()
+
+	g := gateway.New(protos.NewCurrencyClient(conn), l)
+
+	// CORS
+	ch := gohandlers.CORS(gohandlers.AllowedOrigins(strings.Split(*allowedOrigins, ",")))
+
+	// there is no write timeout as rate streams are long lived
+	s := http.Server{
+		Addr:              *bindAddress,
+		Handler:           ch(g.Handler()),
+		ErrorLog:          l.StandardLogger(&hclog.StandardLoggerOptions{}),
+		IdleTimeout:       120 * time.Second,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		l.Info("Starting gateway", "address", *bindAddress, "currency", *currencyAddress)
+
+		err := s.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			l.Error("Unable to serve", "error", err)
+			os.Exit(1)
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	<-ctx.Done()
+	l.Info("Received terminate, graceful shutdown")
+
+	// open streams end when the currency connection closes
+	conn.Close()
+
+	tc, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	s.Shutdown(tc)
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hnsia/go-nic/currency/data"
+	"github.com/hnsia/go-nic/currency/server"
+)
+
+// validateConfig checks the values parsed from the environment, all invalid
+// values are returned so they can be fixed at once
+func validateConfig() error {
+	var errs []error
+	invalid := func(format string, a ...any) {
+		errs = append(errs, fmt.Errorf(format, a...))
+	}
+
+	if *bindAddress == "" {
+		invalid("BIND_ADDRESS is required")
+	}
+
+	if hclog.LevelFromString(*logLevel) == hclog.NoLevel {
+		invalid("LOG_LEVEL %q is not a valid log level", *logLevel)
+	}
+
+	for _, p := range strings.Split(*rateProvider, ",") {
+		switch strings.TrimSpace(p) {
+		case "ecb":
+			if *ecbURL == "" {
+				invalid("ECB_URL is required for the ecb provider")
+			}
+		case "file":
+			if *rateFile == "" {
+				invalid("RATE_FILE is required for the file provider")
+			}
+		case "memory":
+		default:
+			invalid("RATE_PROVIDER %q is not a known provider", p)
+		}
+	}
+
+	if *providerTimeout < 0 {
+		invalid("PROVIDER_TIMEOUT can not be negative")
+	}
+
+	if *providerRetries < 0 {
+		invalid("PROVIDER_RETRIES can not be negative")
+	}
+
+	if *providerBackoff < 0 {
+		invalid("PROVIDER_BACKOFF can not be negative")
+	}
+
+	if *providerFailureThreshold < 0 {
+		invalid("PROVIDER_FAILURE_THRESHOLD can not be negative")
+	}
+
+	if *providerCoolDown < 0 {
+		invalid("PROVIDER_COOL_DOWN can not be negative")
+	}
+
+	if *refreshInterval < 0 {
+		invalid("REFRESH_INTERVAL can not be negative")
+	}
+
+	if *refreshRetry < 0 {
+		invalid("REFRESH_RETRY_INTERVAL can not be negative")
+	}
+
+	if t, err := parseTimeOfDay(*refreshTime); err != nil {
+		invalid("REFRESH_TIME: %s", err)
+	} else if t == 0 {
+		invalid("REFRESH_TIME must be after 00:00")
+	}
+
+	if _, err := time.LoadLocation(*refreshTimezone); err != nil {
+		invalid("REFRESH_TIMEZONE %q is not a known time zone", *refreshTimezone)
+	}
+
+	for _, r := range splitList(*candleResolutions) {
+		if _, err := data.ParseResolution(r); err != nil {
+			invalid("CANDLE_RESOLUTIONS: %s", err)
+		}
+	}
+
+	for _, p := range splitList(*candlePairs) {
+		codes := strings.Split(p, "/")
+		if len(codes) != 2 || len(codes[0]) != 3 || len(codes[1]) != 3 {
+			invalid("CANDLE_PAIRS %q is not a currency pair written as BASE/DEST", p)
+		}
+	}
+
+	if *candleSize <= 0 {
+		invalid("CANDLE_SIZE must be greater than 0")
+	}
+
+	if *replaySpeed <= 0 {
+		invalid("REPLAY_SPEED must be greater than 0")
+	}
+
+	if *replayFile != "" && *simulate {
+		invalid("SIMULATION can not be enabled with REPLAY_FILE, the recording already contains the simulated changes")
+	}
+
+	if *replayFile != "" && *replayFile == *recordFile {
+		invalid("RECORD_FILE can not be the same file as REPLAY_FILE")
+	}
+
+	if *simulationInterval <= 0 {
+		invalid("SIMULATION_INTERVAL must be greater than 0")
+	}
+
+	if *simulationMaxDrift < 0 || *simulationMaxDrift >= 1 {
+		invalid("SIMULATION_MAX_DRIFT must be at least 0 and less than 1")
+	}
+
+	if *simulationMeanReversion < 0 || *simulationMeanReversion > 1 {
+		invalid("SIMULATION_MEAN_REVERSION must be between 0 and 1")
+	}
+
+	if *healthMaxRateAge < 0 {
+		invalid("HEALTH_MAX_RATE_AGE can not be negative")
+	}
+
+	if *healthInterval <= 0 {
+		invalid("HEALTH_CHECK_INTERVAL must be greater than 0")
+	}
+
+	if *subscriberQueueSize <= 0 {
+		invalid("SUBSCRIBER_QUEUE_SIZE must be greater than 0")
+	}
+
+	if _, err := server.ParseOverflowPolicy(*subscriberOverflow); err != nil {
+		invalid("SUBSCRIBER_OVERFLOW_POLICY: %s", err)
+	}
+
+	if *shutdownTimeout <= 0 {
+		invalid("SHUTDOWN_TIMEOUT must be greater than 0")
+	}
+
+	if (*tlsCert == "") != (*tlsKey == "") {
+		invalid("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+
+	if *tlsClientCA != "" && *tlsCert == "" {
+		invalid("TLS_CLIENT_CA_FILE requires TLS_CERT_FILE and TLS_KEY_FILE")
+	}
+
+	return errors.Join(errs...)
+}
+
+// parseTimeOfDay returns the time since midnight for a time written as HH:MM
+func parseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a time of day written as HH:MM", s)
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
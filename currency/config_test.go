@@ -0,0 +1,66 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hnsia/go-nic/currency/data"
+	"github.com/nicholasjackson/env"
+)
+
+func TestValidateConfig(t *testing.T) {
+	// set the defaults
+	err := env.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = validateConfig()
+	if err != nil {
+		t.Fatalf("expected defaults to be valid, got %s", err)
+	}
+
+	*logLevel = "loud"
+	*simulationInterval = 0
+	*tlsClientCA = "ca.pem"
+	*subscriberOverflow = "block"
+	*rateProvider = "ecb,fixer"
+	*refreshTime = "4pm"
+	*replaySpeed = 0
+	defer env.Parse()
+
+	err = validateConfig()
+	if err == nil {
+		t.Fatal("expected invalid configuration")
+	}
+
+	// every invalid value is reported
+	for _, v := range []string{"LOG_LEVEL", "SIMULATION_INTERVAL", "TLS_CLIENT_CA_FILE", "SUBSCRIBER_OVERFLOW_POLICY", "fixer", "REFRESH_TIME", "REPLAY_SPEED"} {
+		if !strings.Contains(err.Error(), v) {
+			t.Errorf("expected error for %s, got %s", v, err)
+		}
+	}
+}
+
+func TestDefaultMaxRateAgeCoversTargetClosures(t *testing.T) {
+	err := env.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the longest gap between the default refreshes is over Easter, from the
+	// Thursday before to the Tuesday after. The limit must also cover a day
+	// of failed refreshes
+	s := data.Schedule{}
+	longest := time.Duration(0)
+	for at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC); at.Year() < 2040; {
+		next := s.Next(at)
+		longest = max(longest, next.Sub(at))
+		at = next
+	}
+
+	if longest+24*time.Hour > *healthMaxRateAge {
+		t.Fatalf("expected HEALTH_MAX_RATE_AGE %s to exceed the longest gap between refreshes %s by a day", *healthMaxRateAge, longest)
+	}
+}
@@ -0,0 +1,401 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// Resolution is the period covered by a candle
+type Resolution time.Duration
+
+// Supported candle resolutions
+const (
+	Minute = Resolution(time.Minute)
+	Hour   = Resolution(time.Hour)
+	Day    = Resolution(24 * time.Hour)
+)
+
+var resolutionNames = map[Resolution]string{
+	Minute: "1m",
+	Hour:   "1h",
+	Day:    "1d",
+}
+
+func (r Resolution) String() string {
+	if n, ok := resolutionNames[r]; ok {
+		return n
+	}
+
+	return time.Duration(r).String()
+}
+
+// ParseResolution returns the resolution for the name [1m, 1h, 1d]
+func ParseResolution(name string) (Resolution, error) {
+	for r, n := range resolutionNames {
+		if n == name {
+			return r, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown resolution %q, expected 1m, 1h or 1d", name)
+}
+
+// Errors returned by Candles.Get for data which is not recorded
+var (
+	ErrPairNotTracked       = errors.New("currency pair is not tracked")
+	ErrResolutionNotTracked = errors.New("resolution is not tracked")
+)
+
+// Candle contains the open, high, low and close mid rates for a currency
+// pair during the period which begins at Start
+type Candle struct {
+	Start time.Time `json:"start"`
+	Open  float64   `json:"open"`
+	High  float64   `json:"high"`
+	Low   float64   `json:"low"`
+	Close float64   `json:"close"`
+}
+
+// invert returns the candle for the inverse currency pair
+func (c Candle) invert() Candle {
+	return Candle{Start: c.Start, Open: 1 / c.Open, High: 1 / c.Low, Low: 1 / c.High, Close: 1 / c.Close}
+}
+
+// ring is a fixed size buffer of candles, the oldest candle is
+// overwritten when the buffer is full
+type ring struct {
+	candles []Candle
+	next    int // index the next candle is written to
+	full    bool
+}
+
+func newRing(size int) *ring {
+	return &ring{candles: make([]Candle, size)}
+}
+
+// add appends the candle overwriting the oldest candle when full
+func (r *ring) add(c Candle) {
+	r.candles[r.next] = c
+	r.next = (r.next + 1) % len(r.candles)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// last returns the most recent candle, nil when the ring is empty
+func (r *ring) last() *Candle {
+	if r.next == 0 && !r.full {
+		return nil
+	}
+
+	return &r.candles[(r.next-1+len(r.candles))%len(r.candles)]
+}
+
+// all returns the candles from oldest to newest
+func (r *ring) all() []Candle {
+	if !r.full {
+		return append([]Candle{}, r.candles[:r.next]...)
+	}
+
+	return append(append([]Candle{}, r.candles[r.next:]...), r.candles[:r.next]...)
+}
+
+// CandleConfig configures the candles recorded by Candles
+type CandleConfig struct {
+	// Pairs are the currency pairs written as BASE/DEST, when empty every
+	// currency is tracked against EUR. The inverse of a tracked pair can
+	// also be requested
+	Pairs []string
+	// Resolutions are the periods candles are recorded for
+	Resolutions []Resolution
+	// Size is the number of candles kept for each pair and resolution
+	Size int
+	// Path is the file the candles are persisted to, empty disables persistence
+	Path string
+}
+
+// Candles aggregates changes to the rates into candles for each tracked
+// currency pair and resolution, it is safe for concurrent use
+type Candles struct {
+	cfg CandleConfig
+
+	// mu guards rings, which is keyed by pair then resolution
+	mu    sync.RWMutex
+	rings map[string]map[Resolution]*ring
+}
+
+// NewCandles creates an empty set of candles, when a path is configured the
+// candles saved by a previous run are loaded
+func NewCandles(cfg CandleConfig) (*Candles, error) {
+	if cfg.Size <= 0 {
+		return nil, fmt.Errorf("candle size must be greater than 0")
+	}
+
+	// the pairs are normalized without changing the caller's slice
+	cfg.Pairs = slices.Clone(cfg.Pairs)
+	for i, p := range cfg.Pairs {
+		base, dest, ok := splitPair(p)
+		if !ok {
+			return nil, fmt.Errorf("invalid currency pair %q, expected BASE/DEST", p)
+		}
+
+		cfg.Pairs[i] = base + "/" + dest
+	}
+
+	c := &Candles{cfg: cfg, rings: map[string]map[Resolution]*ring{}}
+	if cfg.Path == "" {
+		return c, nil
+	}
+
+	err := c.load()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// WithCandles records every change to the rates in the candles
+func WithCandles(c *Candles) Option {
+	return func(e *ExchangeRates) {
+		e.candles = c
+	}
+}
+
+// Candles returns the candles the rates are recorded in, nil when candles are not recorded
+func (e *ExchangeRates) Candles() *Candles {
+	return e.candles
+}
+
+// splitPair returns the upper case currencies in a pair written as BASE/DEST
+func splitPair(pair string) (string, string, bool) {
+	codes := strings.Split(strings.ToUpper(pair), "/")
+	if len(codes) != 2 || len(codes[0]) != 3 || len(codes[1]) != 3 || codes[0] == codes[1] {
+		return "", "", false
+	}
+
+	return codes[0], codes[1], true
+}
+
+// pairs returns the pairs to record for the rates
+func (c *Candles) pairs(rates map[string]float64) []string {
+	if len(c.cfg.Pairs) > 0 {
+		return c.cfg.Pairs
+	}
+
+	pairs := []string{}
+	for k := range rates {
+		if k != "EUR" {
+			pairs = append(pairs, "EUR/"+k)
+		}
+	}
+
+	return pairs
+}
+
+// record adds the rates at time t to the candles, rates are quoted against EUR.
+// Nothing is recorded for nil candles
+func (c *Candles) record(t time.Time, rates map[string]float64) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, p := range c.pairs(rates) {
+		base, dest, _ := splitPair(p)
+
+		br, bok := rates[base]
+		dr, dok := rates[dest]
+		if !bok || !dok {
+			continue
+		}
+
+		rate := dr / br
+
+		if c.rings[p] == nil {
+			c.rings[p] = map[Resolution]*ring{}
+		}
+
+		for _, res := range c.cfg.Resolutions {
+			r, ok := c.rings[p][res]
+			if !ok {
+				r = newRing(c.cfg.Size)
+				c.rings[p][res] = r
+			}
+
+			start := t.Truncate(time.Duration(res)).UTC()
+			last := r.last()
+
+			switch {
+			case last == nil || start.After(last.Start):
+				r.add(Candle{Start: start, Open: rate, High: rate, Low: rate, Close: rate})
+			case start.Equal(last.Start):
+				last.High = max(last.High, rate)
+				last.Low = min(last.Low, rate)
+				last.Close = rate
+			}
+		}
+	}
+}
+
+// tracked returns true when the resolution is recorded
+func (c *Candles) tracked(res Resolution) bool {
+	return slices.Contains(c.cfg.Resolutions, res)
+}
+
+// Get returns the candles for the pair at the resolution whose period overlaps
+// from and to. The candles for the inverse of a tracked pair are calculated
+// from the tracked pair
+func (c *Candles) Get(pair string, res Resolution, from, to time.Time) ([]Candle, error) {
+	base, dest, ok := splitPair(pair)
+	if !ok {
+		return nil, fmt.Errorf("invalid currency pair %q, expected BASE/DEST", pair)
+	}
+
+	if !c.tracked(res) {
+		return nil, fmt.Errorf("%w: %s", ErrResolutionNotTracked, res)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	invert := false
+	r, ok := c.rings[base+"/"+dest][res]
+	if !ok {
+		r, ok = c.rings[dest+"/"+base][res]
+		invert = true
+	}
+
+	if !ok {
+		// configured pairs are tracked before their first rate is recorded
+		if slices.Contains(c.cfg.Pairs, base+"/"+dest) || slices.Contains(c.cfg.Pairs, dest+"/"+base) {
+			return []Candle{}, nil
+		}
+
+		return nil, fmt.Errorf("%w: %s/%s", ErrPairNotTracked, base, dest)
+	}
+
+	from = from.Truncate(time.Duration(res))
+	candles := []Candle{}
+	for _, cd := range r.all() {
+		if cd.Start.Before(from) || cd.Start.After(to) {
+			continue
+		}
+
+		if invert {
+			cd = cd.invert()
+		}
+
+		candles = append(candles, cd)
+	}
+
+	return candles, nil
+}
+
+// Save writes the candles to the configured path
+func (c *Candles) Save() error {
+	if c.cfg.Path == "" {
+		return nil
+	}
+
+	c.mu.RLock()
+	saved := map[string]map[string][]Candle{}
+	for p, rings := range c.rings {
+		saved[p] = map[string][]Candle{}
+		for res, r := range rings {
+			saved[p][res.String()] = r.all()
+		}
+	}
+	c.mu.RUnlock()
+
+	d, err := json.Marshal(saved)
+	if err != nil {
+		return fmt.Errorf("unable to encode candles: %w", err)
+	}
+
+	err = writeFileAtomic(c.cfg.Path, d)
+	if err != nil {
+		return fmt.Errorf("unable to write candle file: %w", err)
+	}
+
+	return nil
+}
+
+// SaveEvery saves the candles at the interval until the context is cancelled
+func (c *Candles) SaveEvery(ctx context.Context, interval time.Duration, l hclog.Logger) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				err := c.Save()
+				if err != nil {
+					l.Error("Unable to save candles", "error", err, "path", c.cfg.Path)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// load reads the candles written by Save, candles for resolutions which
+// are no longer tracked are dropped
+func (c *Candles) load() error {
+	f, err := os.Open(c.cfg.Path)
+	if err != nil {
+		return fmt.Errorf("unable to open candle file: %w", err)
+	}
+	defer f.Close()
+
+	saved := map[string]map[string][]Candle{}
+	err = json.NewDecoder(f).Decode(&saved)
+	if err != nil {
+		return fmt.Errorf("unable to decode candles: %w", err)
+	}
+
+	for p, resolutions := range saved {
+		if len(c.cfg.Pairs) > 0 && !slices.Contains(c.cfg.Pairs, p) {
+			continue
+		}
+
+		c.rings[p] = map[Resolution]*ring{}
+
+		for name, candles := range resolutions {
+			res, err := ParseResolution(name)
+			if err != nil || !c.tracked(res) {
+				continue
+			}
+
+			sort.Slice(candles, func(i, j int) bool { return candles[i].Start.Before(candles[j].Start) })
+
+			// keep the most recent candles when the size has been reduced
+			if len(candles) > c.cfg.Size {
+				candles = candles[len(candles)-c.cfg.Size:]
+			}
+
+			r := newRing(c.cfg.Size)
+			for _, cd := range candles {
+				r.add(cd)
+			}
+
+			c.rings[p][res] = r
+		}
+	}
+
+	return nil
+}
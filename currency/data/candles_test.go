@@ -0,0 +1,174 @@
+package data
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+func newTestCandles(t *testing.T, cfg CandleConfig) *Candles {
+	c, err := NewCandles(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestCandlesAggregateRates(t *testing.T) {
+	c := newTestCandles(t, CandleConfig{Resolutions: []Resolution{Minute, Hour}, Size: 10})
+
+	start := time.Date(2024, 10, 15, 12, 0, 0, 0, time.UTC)
+	for i, usd := range []float64{1.10, 1.12, 1.09, 1.11, 1.13} {
+		// two rates in each minute
+		c.record(start.Add(time.Duration(i)*30*time.Second), map[string]float64{"EUR": 1, "USD": usd})
+	}
+
+	minutes, err := c.Get("EUR/USD", Minute, start, start.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Candle{
+		{Start: start, Open: 1.10, High: 1.12, Low: 1.10, Close: 1.12},
+		{Start: start.Add(time.Minute), Open: 1.09, High: 1.11, Low: 1.09, Close: 1.11},
+		{Start: start.Add(2 * time.Minute), Open: 1.13, High: 1.13, Low: 1.13, Close: 1.13},
+	}
+
+	if len(minutes) != len(expected) {
+		t.Fatalf("expected %d candles, got %v", len(expected), minutes)
+	}
+
+	for i := range expected {
+		if minutes[i] != expected[i] {
+			t.Fatalf("expected candle %d to be %v, got %v", i, expected[i], minutes[i])
+		}
+	}
+
+	hours, err := c.Get("eur/usd", Hour, start, start.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(hours) != 1 || hours[0] != (Candle{Start: start, Open: 1.10, High: 1.13, Low: 1.09, Close: 1.13}) {
+		t.Fatalf("unexpected hourly candles %v", hours)
+	}
+
+	// the range includes the candle containing from
+	m, _ := c.Get("EUR/USD", Minute, start.Add(90*time.Second), start.Add(time.Hour))
+	if len(m) != 2 || !m[0].Start.Equal(start.Add(time.Minute)) {
+		t.Fatalf("expected the last two candles, got %v", m)
+	}
+}
+
+func TestCandlesAreBounded(t *testing.T) {
+	c := newTestCandles(t, CandleConfig{Resolutions: []Resolution{Minute}, Size: 2})
+
+	start := time.Date(2024, 10, 15, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		c.record(start.Add(time.Duration(i)*time.Minute), map[string]float64{"EUR": 1, "USD": float64(i + 1)})
+	}
+
+	candles, _ := c.Get("EUR/USD", Minute, start, start.Add(time.Hour))
+	if len(candles) != 2 || candles[0].Open != 2 || candles[1].Open != 3 {
+		t.Fatalf("expected the two newest candles, got %v", candles)
+	}
+}
+
+func TestCandlesTrackedPairs(t *testing.T) {
+	pairs := []string{"gbp/usd", "EUR/JPY"}
+	c := newTestCandles(t, CandleConfig{Pairs: pairs, Resolutions: []Resolution{Minute}, Size: 10})
+
+	// the pairs are normalized in a copy
+	if pairs[0] != "gbp/usd" {
+		t.Fatalf("expected the configured pairs to be unchanged, got %v", pairs)
+	}
+
+	start := time.Date(2024, 10, 15, 12, 0, 0, 0, time.UTC)
+	c.record(start, map[string]float64{"EUR": 1, "USD": 1.1, "GBP": 0.8})
+
+	// the inverse pair is calculated from the tracked pair
+	candles, err := c.Get("USD/GBP", Minute, start, start)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(candles) != 1 || candles[0].Close != 0.8/1.1 {
+		t.Fatalf("expected the inverted rate, got %v", candles)
+	}
+
+	_, err = c.Get("EUR/USD", Minute, start, start)
+	if !errors.Is(err, ErrPairNotTracked) {
+		t.Fatalf("expected untracked pair, got %v", err)
+	}
+
+	_, err = c.Get("GBP/USD", Hour, start, start)
+	if !errors.Is(err, ErrResolutionNotTracked) {
+		t.Fatalf("expected untracked resolution, got %v", err)
+	}
+
+	// a tracked pair without rates has no candles
+	candles, err = c.Get("EUR/JPY", Minute, start, start)
+	if err != nil || len(candles) != 0 {
+		t.Fatalf("expected no candles, got %v %v", candles, err)
+	}
+}
+
+func TestCandlesArePersisted(t *testing.T) {
+	cfg := CandleConfig{Resolutions: []Resolution{Minute, Day}, Size: 10, Path: filepath.Join(t.TempDir(), "candles.json")}
+	c := newTestCandles(t, cfg)
+
+	start := time.Date(2024, 10, 15, 12, 0, 0, 0, time.UTC)
+	c.record(start, map[string]float64{"EUR": 1, "USD": 1.1})
+	c.record(start.Add(time.Minute), map[string]float64{"EUR": 1, "USD": 1.2})
+
+	err := c.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// resolutions which are no longer tracked are dropped
+	cfg.Resolutions = []Resolution{Minute}
+	loaded := newTestCandles(t, cfg)
+
+	candles, err := loaded.Get("EUR/USD", Minute, start, start.Add(time.Hour))
+	if err != nil || len(candles) != 2 || candles[1].Close != 1.2 {
+		t.Fatalf("expected the saved candles, got %v %v", candles, err)
+	}
+
+	// new rates continue the last candle
+	loaded.record(start.Add(90*time.Second), map[string]float64{"EUR": 1, "USD": 1.3})
+	candles, _ = loaded.Get("EUR/USD", Minute, start, start.Add(time.Hour))
+	if len(candles) != 2 || candles[1].High != 1.3 {
+		t.Fatalf("expected the loaded candle to be updated, got %v", candles)
+	}
+}
+
+func TestTicksAreRecordedInCandles(t *testing.T) {
+	c := newTestCandles(t, CandleConfig{Resolutions: []Resolution{Day}, Size: 10})
+
+	tr, err := NewRates(hclog.NewNullLogger(), NewMemory(map[string]float64{"USD": 1.1}), WithCandles(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tr.Tick(NewSimulator(Simulation{Seed: 1, MaxDrift: 0.01}))
+
+	candles, err := tr.Candles().Get("EUR/USD", Day, time.Now().Add(-24*time.Hour), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rate, _ := tr.GetRate("EUR", "USD")
+	if len(candles) == 0 || candles[len(candles)-1].Close != rate {
+		t.Fatalf("expected the simulated rate to close the candle, got %v", candles)
+	}
+
+	// the fetched rate opens the candle unless the day changed between fetch and tick
+	if last := candles[len(candles)-1]; len(candles) == 1 && last.Open != 1.1 {
+		t.Fatalf("expected the fetched rate to open the candle, got %v", last)
+	}
+}
@@ -0,0 +1,212 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hnsia/go-nic/currency/metrics"
+)
+
+// Link is a provider in a Chain along with the settings for fetching from it
+type Link struct {
+	Provider RateProvider
+	// Name identifies the provider in logs and metrics, the name of the
+	// provider is used when empty. Set it when a chain contains several
+	// providers of the same kind, e.g. two ECB mirrors
+	Name string
+	// Timeout limits each attempt to fetch the rates, 0 disables the timeout
+	Timeout time.Duration
+	// Retries is the number of times a failed fetch is retried before
+	// moving to the next provider
+	Retries int
+	// Backoff is the wait before the first retry, it doubles for every retry
+	Backoff time.Duration
+	// FailureThreshold is the number of consecutive failed fetches which open
+	// the circuit, the provider is skipped while the circuit is open. 0
+	// disables the circuit breaker
+	FailureThreshold int
+	// CoolDown is the time the circuit stays open, after it has passed a
+	// single fetch is allowed to test if the provider has recovered
+	CoolDown time.Duration
+}
+
+// link is a Link with the state of its circuit breaker
+type link struct {
+	Link
+	failures  int       // consecutive failed fetches
+	openUntil time.Time // provider is skipped until this time
+}
+
+// Chain is a RateProvider which fetches from an ordered list of providers,
+// when a provider fails the next provider in the list is used. It is safe
+// for concurrent use
+type Chain struct {
+	log     hclog.Logger
+	metrics *metrics.Metrics
+	now     func() time.Time
+	sleep   func(ctx context.Context, d time.Duration) error
+
+	// mu guards the links and the active provider, fetches are serialized
+	mu         sync.Mutex
+	links      []*link
+	active     string
+	activeLink *link // link which supplied the last rates, nil before the first fetch
+}
+
+// NewChain creates a provider which tries the links in order, m may be nil
+// when metrics are not required
+func NewChain(l hclog.Logger, m *metrics.Metrics, links ...Link) *Chain {
+	c := &Chain{log: l, metrics: m, now: time.Now, sleep: sleep}
+	for _, lk := range links {
+		if lk.Name == "" {
+			lk.Name = lk.Provider.Name()
+		}
+
+		c.links = append(c.links, &link{Link: lk})
+	}
+
+	if len(c.links) > 0 {
+		c.active = c.links[0].Name
+	}
+
+	return c
+}
+
+// Name returns the name of the provider which supplied the last rates,
+// before the first fetch it is the first provider in the chain
+func (c *Chain) Name() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.active
+}
+
+// Rates returns the rates from the first provider in the chain which
+// succeeds, providers whose circuit is open are skipped. The errors from
+// every provider are returned when all of them fail
+func (c *Chain) Rates(ctx context.Context) (map[string]float64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var errs []error
+	for _, l := range c.links {
+		now := c.now()
+		if now.Before(l.openUntil) {
+			c.log.Debug("Skipping rate provider, circuit is open", "provider", l.Name, "until", l.openUntil)
+			errs = append(errs, fmt.Errorf("%s: circuit open until %s", l.Name, l.openUntil.Format(time.RFC3339)))
+			continue
+		}
+
+		rates, err := c.fetch(ctx, l)
+		if err == nil {
+			l.failures = 0
+			c.metrics.CircuitOpen(l.Name, false)
+			c.activate(l)
+
+			return rates, nil
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", l.Name, err))
+
+		// a cancelled fetch says nothing about the health of the provider
+		if ctx.Err() != nil {
+			break
+		}
+
+		l.failures++
+		if l.FailureThreshold > 0 && l.failures >= l.FailureThreshold {
+			l.openUntil = c.now().Add(l.CoolDown)
+			c.log.Warn("Opening circuit for rate provider", "provider", l.Name, "failures", l.failures, "cool_down", l.CoolDown)
+			c.metrics.CircuitOpen(l.Name, true)
+		}
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// fetch calls the provider retrying failed attempts with an exponential backoff
+func (c *Chain) fetch(ctx context.Context, l *link) (map[string]float64, error) {
+	backoff := l.Backoff
+
+	for attempt := 0; ; attempt++ {
+		rates, err := c.attempt(ctx, l)
+		c.metrics.ProviderAttempt(l.Name, err)
+		if err == nil {
+			return rates, nil
+		}
+
+		c.log.Warn("Unable to fetch rates", "provider", l.Name, "attempt", attempt+1, "error", err)
+
+		if attempt >= l.Retries {
+			return nil, err
+		}
+
+		if serr := c.sleep(ctx, backoff); serr != nil {
+			return nil, err
+		}
+
+		backoff *= 2
+	}
+}
+
+// attempt calls the provider once applying the timeout for the link
+func (c *Chain) attempt(ctx context.Context, l *link) (map[string]float64, error) {
+	if l.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.Timeout)
+		defer cancel()
+	}
+
+	return l.Provider.Rates(ctx)
+}
+
+// Published returns the publication date of the last rates when the provider
+// which supplied them is a PublishedProvider
+func (c *Chain) Published() (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.activeLink == nil {
+		return time.Time{}, false
+	}
+
+	pp, ok := c.activeLink.Provider.(PublishedProvider)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return pp.Published()
+}
+
+// activate records the provider which supplied the rates
+func (c *Chain) activate(active *link) {
+	name := active.Name
+	c.activeLink = active
+	if name != c.active {
+		c.log.Warn("Switched rate provider", "from", c.active, "to", name)
+	}
+
+	c.active = name
+	c.log.Info("Fetched rates", "provider", name)
+
+	for _, l := range c.links {
+		c.metrics.ActiveProvider(l.Name, l.Name == name)
+	}
+}
+
+// sleep waits for the duration or until the context is cancelled
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
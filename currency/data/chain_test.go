@@ -0,0 +1,191 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// flakyServer is an ECB feed which fails while healthy is false and
+// responds after the delay, requests are counted
+type flakyServer struct {
+	*httptest.Server
+	healthy  atomic.Bool
+	delay    time.Duration
+	requests atomic.Int32
+}
+
+func newFlakyServer(t *testing.T, healthy bool, delay time.Duration) *flakyServer {
+	f := &flakyServer{delay: delay}
+	f.healthy.Store(healthy)
+
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.requests.Add(1)
+
+		select {
+		case <-time.After(f.delay):
+		case <-r.Context().Done():
+			return
+		}
+
+		if !f.healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		fmt.Fprint(w, ecbDaily)
+	}))
+	t.Cleanup(f.Close)
+
+	return f
+}
+
+// noSleep records the backoff without waiting
+func noSleep(waits *[]time.Duration) func(context.Context, time.Duration) error {
+	return func(ctx context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return nil
+	}
+}
+
+func TestChainFailsOver(t *testing.T) {
+	primary := newFlakyServer(t, false, 0)
+	backup := newFlakyServer(t, true, 0)
+
+	c := NewChain(hclog.NewNullLogger(), nil,
+		Link{Provider: NewECB(primary.URL), Retries: 3, Backoff: 10 * time.Millisecond},
+		Link{Provider: NewECB(backup.URL), Name: "ecb-backup"},
+	)
+
+	waits := []time.Duration{}
+	c.sleep = noSleep(&waits)
+
+	if c.Name() != "ecb" {
+		t.Fatalf("expected the first provider to be active, got %s", c.Name())
+	}
+
+	rates, err := c.Rates(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rates["USD"] != 1.0895 || c.Name() != "ecb-backup" {
+		t.Fatalf("expected rates from the backup, got %v from %s", rates, c.Name())
+	}
+
+	// the first attempt and three retries with an exponential backoff
+	if n := primary.requests.Load(); n != 4 {
+		t.Fatalf("expected 4 requests to the primary, got %d", n)
+	}
+
+	expected := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond}
+	if fmt.Sprint(waits) != fmt.Sprint(expected) {
+		t.Fatalf("expected backoff %v, got %v", expected, waits)
+	}
+}
+
+func TestChainTimeout(t *testing.T) {
+	slow := newFlakyServer(t, true, time.Second)
+	backup := newFlakyServer(t, true, 0)
+
+	c := NewChain(hclog.NewNullLogger(), nil,
+		Link{Provider: NewECB(slow.URL), Name: "slow", Timeout: 20 * time.Millisecond},
+		Link{Provider: NewECB(backup.URL), Name: "backup"},
+	)
+
+	start := time.Now()
+	_, err := c.Rates(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Name() != "backup" || time.Since(start) > 500*time.Millisecond {
+		t.Fatalf("expected the slow provider to time out, got %s after %s", c.Name(), time.Since(start))
+	}
+}
+
+func TestChainCircuitBreaker(t *testing.T) {
+	primary := newFlakyServer(t, false, 0)
+	backup := newFlakyServer(t, true, 0)
+
+	c := NewChain(hclog.NewNullLogger(), nil,
+		Link{Provider: NewECB(primary.URL), Name: "primary", FailureThreshold: 2, CoolDown: time.Minute},
+		Link{Provider: NewECB(backup.URL), Name: "backup"},
+	)
+
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	fetch := func() {
+		t.Helper()
+
+		_, err := c.Rates(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the circuit opens after the second failure and the primary is skipped
+	for i := 0; i < 3; i++ {
+		fetch()
+	}
+
+	if n := primary.requests.Load(); n != 2 {
+		t.Fatalf("expected the primary to be skipped once the circuit opened, got %d requests", n)
+	}
+
+	// the primary is tried again after the cool down
+	primary.healthy.Store(true)
+	now = now.Add(time.Minute)
+	fetch()
+
+	if n := primary.requests.Load(); n != 3 || c.Name() != "primary" {
+		t.Fatalf("expected the primary to recover, got %d requests and %s active", n, c.Name())
+	}
+}
+
+func TestChainReturnsAllErrors(t *testing.T) {
+	a := newFlakyServer(t, false, 0)
+	b := newFlakyServer(t, false, 0)
+
+	c := NewChain(hclog.NewNullLogger(), nil,
+		Link{Provider: NewECB(a.URL), Name: "a"},
+		Link{Provider: NewECB(b.URL), Name: "b"},
+	)
+
+	_, err := NewRates(hclog.NewNullLogger(), c)
+	if err == nil {
+		t.Fatal("expected error when every provider fails")
+	}
+
+	for _, n := range []string{"a: ", "b: "} {
+		if !strings.Contains(err.Error(), n) {
+			t.Fatalf("expected error for provider %s, got %s", n, err)
+		}
+	}
+}
+
+func TestRatesSourceIsActiveProvider(t *testing.T) {
+	primary := newFlakyServer(t, false, 0)
+
+	c := NewChain(hclog.NewNullLogger(), nil,
+		Link{Provider: NewECB(primary.URL)},
+		Link{Provider: NewMemory(SampleRates)},
+	)
+
+	tr, err := NewRates(hclog.NewNullLogger(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tr.Provenance().Source != "memory" {
+		t.Fatalf("expected the memory provider as the source, got %s", tr.Provenance().Source)
+	}
+}
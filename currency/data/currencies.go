@@ -0,0 +1,88 @@
+package data
+
+import "sort"
+
+// CurrencyInfo contains the ISO 4217 details for a currency
+type CurrencyInfo struct {
+	Code        string // alphabetic code, e.g. EUR
+	Name        string
+	NumericCode string // three digit numeric code, e.g. 978
+	MinorUnits  int    // number of decimal digits in the minor unit
+	Symbol      string
+}
+
+// isoCurrencies contains the details of the currencies published by the ECB
+var isoCurrencies = map[string]CurrencyInfo{
+	"AUD": {"AUD", "Australian Dollar", "036", 2, "A$"},
+	"BGN": {"BGN", "Bulgarian Lev", "975", 2, "лв"},
+	"BRL": {"BRL", "Brazilian Real", "986", 2, "R$"},
+	"CAD": {"CAD", "Canadian Dollar", "124", 2, "C$"},
+	"CHF": {"CHF", "Swiss Franc", "756", 2, "CHF"},
+	"CNY": {"CNY", "Yuan Renminbi", "156", 2, "¥"},
+	"CZK": {"CZK", "Czech Koruna", "203", 2, "Kč"},
+	"DKK": {"DKK", "Danish Krone", "208", 2, "kr"},
+	"EUR": {"EUR", "Euro", "978", 2, "€"},
+	"GBP": {"GBP", "Pound Sterling", "826", 2, "£"},
+	"HKD": {"HKD", "Hong Kong Dollar", "344", 2, "HK$"},
+	"HRK": {"HRK", "Kuna", "191", 2, "kn"},
+	"HUF": {"HUF", "Forint", "348", 2, "Ft"},
+	"IDR": {"IDR", "Rupiah", "360", 2, "Rp"},
+	"ILS": {"ILS", "New Israeli Sheqel", "376", 2, "₪"},
+	"INR": {"INR", "Indian Rupee", "356", 2, "₹"},
+	"ISK": {"ISK", "Iceland Krona", "352", 0, "kr"},
+	"JPY": {"JPY", "Yen", "392", 0, "¥"},
+	"KRW": {"KRW", "Won", "410", 0, "₩"},
+	"MXN": {"MXN", "Mexican Peso", "484", 2, "Mex$"},
+	"MYR": {"MYR", "Malaysian Ringgit", "458", 2, "RM"},
+	"NOK": {"NOK", "Norwegian Krone", "578", 2, "kr"},
+	"NZD": {"NZD", "New Zealand Dollar", "554", 2, "NZ$"},
+	"PHP": {"PHP", "Philippine Peso", "608", 2, "₱"},
+	"PLN": {"PLN", "Zloty", "985", 2, "zł"},
+	"RON": {"RON", "Romanian Leu", "946", 2, "lei"},
+	"RUB": {"RUB", "Russian Ruble", "643", 2, "₽"},
+	"SEK": {"SEK", "Swedish Krona", "752", 2, "kr"},
+	"SGD": {"SGD", "Singapore Dollar", "702", 2, "S$"},
+	"THB": {"THB", "Baht", "764", 2, "฿"},
+	"TRY": {"TRY", "Turkish Lira", "949", 2, "₺"},
+	"USD": {"USD", "US Dollar", "840", 2, "$"},
+	"ZAR": {"ZAR", "Rand", "710", 2, "R"},
+}
+
+// Info returns the ISO 4217 details for the currency code, currencies
+// which are not known only contain the code and use 2 minor unit digits
+func Info(code string) CurrencyInfo {
+	if ci, ok := isoCurrencies[code]; ok {
+		return ci
+	}
+
+	return CurrencyInfo{Code: code, MinorUnits: 2}
+}
+
+// MinorUnits returns the number of decimal digits in the minor unit of the currency
+func MinorUnits(code string) int {
+	return Info(code).MinorUnits
+}
+
+// Supported returns true when there is a rate for the currency code
+func (e *ExchangeRates) Supported(code string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	_, ok := e.rates[code]
+	return ok
+}
+
+// Currencies returns the details of every currency which has a rate, sorted by code
+func (e *ExchangeRates) Currencies() []CurrencyInfo {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	cis := make([]CurrencyInfo, 0, len(e.rates))
+	for code := range e.rates {
+		cis = append(cis, Info(code))
+	}
+
+	sort.Slice(cis, func(i, j int) bool { return cis[i].Code < cis[j].Code })
+
+	return cis
+}
@@ -0,0 +1,160 @@
+package data
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Locations of the reference rates published by the European Central Bank
+const (
+	ECBDailyURL   = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+	ECB90DayURL   = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist-90d.xml"
+	ECBHistoryURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist.xml"
+)
+
+// ECB is an implementation of the RateProvider and HistoricalProvider
+// interfaces which fetches the reference rates XML published by the European Central Bank.
+// The url can point to any of the daily, 90 day or full history feeds,
+// a local copy of a feed can be used with a file:// url
+type ECB struct {
+	client *http.Client
+	url    string
+
+	mu        sync.Mutex
+	published time.Time // publication date of the rates last returned by Rates
+}
+
+// NewECB creates a new ECB provider which fetches rates from the given url
+func NewECB(url string) *ECB {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.RegisterProtocol("file", http.NewFileTransport(http.Dir("/")))
+
+	return &ECB{client: &http.Client{Transport: t}, url: url}
+}
+
+// Name returns the name of the provider
+func (e *ECB) Name() string {
+	return "ecb"
+}
+
+// Rates fetches the feed and returns the most recently published rates
+func (e *ECB) Rates(ctx context.Context) (map[string]float64, error) {
+	days, err := e.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// the feeds list the most recent day first
+	if len(days) == 0 {
+		return nil, fmt.Errorf("no rates found in feed %s", e.url)
+	}
+
+	rates, err := days[0].rates()
+	if err != nil {
+		return nil, err
+	}
+
+	published, err := time.Parse(time.DateOnly, days[0].Time)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %s in feed: %w", days[0].Time, err)
+	}
+
+	e.mu.Lock()
+	e.published = published
+	e.mu.Unlock()
+
+	return rates, nil
+}
+
+// Published returns the publication date of the rates last returned by Rates
+func (e *ECB) Published() (time.Time, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.published, !e.published.IsZero()
+}
+
+// HistoricalRates fetches the feed and returns the rates for every
+// day it contains, keyed by the publication date
+func (e *ECB) HistoricalRates(ctx context.Context) (map[time.Time]map[string]float64, error) {
+	days, err := e.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	history := map[time.Time]map[string]float64{}
+	for _, d := range days {
+		t, err := time.Parse(time.DateOnly, d.Time)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %s in feed: %w", d.Time, err)
+		}
+
+		rates, err := d.rates()
+		if err != nil {
+			return nil, err
+		}
+
+		history[t] = rates
+	}
+
+	return history, nil
+}
+
+func (e *ECB) fetch(ctx context.Context) ([]CubeDay, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("expected error code 200, got %d instead", resp.StatusCode)
+	}
+
+	md := &Cubes{}
+	err = xml.NewDecoder(resp.Body).Decode(&md)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode rates: %w", err)
+	}
+
+	return md.Days, nil
+}
+
+type Cubes struct {
+	Days []CubeDay `xml:"Cube>Cube"`
+}
+
+// CubeDay contains the rates published on a single day
+type CubeDay struct {
+	Time     string `xml:"time,attr"`
+	CubeData []Cube `xml:"Cube"`
+}
+
+type Cube struct {
+	Currency string `xml:"currency,attr"`
+	Rate     string `xml:"rate,attr"`
+}
+
+func (d CubeDay) rates() (map[string]float64, error) {
+	rates := map[string]float64{}
+	for _, c := range d.CubeData {
+		r, err := strconv.ParseFloat(c.Rate, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		rates[c.Currency] = r
+	}
+
+	return rates, nil
+}
@@ -0,0 +1,92 @@
+package data
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// File is an implementation of the RateProvider interface which reads
+// static rates from a file on the local disk.
+// JSON files contain an object of currency codes to rates, e.g. {"USD": 1.08}
+// CSV files contain rows of currency code and rate, e.g. USD,1.08
+type File struct {
+	path string
+}
+
+// NewFile creates a new file provider for the given path, the format
+// is determined by the file extension (.json or .csv)
+func NewFile(path string) (*File, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".csv":
+	default:
+		return nil, fmt.Errorf("unsupported rate file format %s, expected .json or .csv", path)
+	}
+
+	return &File{path: path}, nil
+}
+
+// Name returns the name of the provider
+func (f *File) Name() string {
+	return "file"
+}
+
+// Rates reads the rates from the file
+func (f *File) Rates(ctx context.Context) (map[string]float64, error) {
+	r, err := os.Open(f.path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open rate file: %w", err)
+	}
+	defer r.Close()
+
+	if strings.ToLower(filepath.Ext(f.path)) == ".json" {
+		return ratesFromJSON(r)
+	}
+
+	return ratesFromCSV(r)
+}
+
+func ratesFromJSON(r io.Reader) (map[string]float64, error) {
+	rates := map[string]float64{}
+
+	err := json.NewDecoder(r).Decode(&rates)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode rate file: %w", err)
+	}
+
+	return rates, nil
+}
+
+func ratesFromCSV(r io.Reader) (map[string]float64, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 2
+	cr.TrimLeadingSpace = true
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read rate file: %w", err)
+	}
+
+	rates := map[string]float64{}
+	for i, rec := range records {
+		rate, err := strconv.ParseFloat(rec[1], 64)
+		if err != nil {
+			// allow an optional header row
+			if i == 0 {
+				continue
+			}
+
+			return nil, fmt.Errorf("invalid rate for currency %s on line %d: %w", rec[0], i+1, err)
+		}
+
+		rates[strings.ToUpper(rec[0])] = rate
+	}
+
+	return rates, nil
+}
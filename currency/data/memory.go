@@ -0,0 +1,100 @@
+package data
+
+import (
+	"context"
+	"sync"
+)
+
+// SampleRates is a snapshot of the ECB reference rates which can be used
+// to run the service without access to the network
+var SampleRates = map[string]float64{
+	"USD": 1.0895,
+	"JPY": 162.85,
+	"BGN": 1.9558,
+	"CZK": 25.305,
+	"DKK": 7.4589,
+	"GBP": 0.83418,
+	"HUF": 400.88,
+	"PLN": 4.2938,
+	"RON": 4.9753,
+	"SEK": 11.3985,
+	"CHF": 0.9383,
+	"ISK": 149.3,
+	"NOK": 11.8795,
+	"TRY": 37.3459,
+	"AUD": 1.6254,
+	"BRL": 6.1367,
+	"CAD": 1.5027,
+	"CNY": 7.7363,
+	"HKD": 8.4645,
+	"IDR": 17010.25,
+	"ILS": 4.1001,
+	"INR": 91.5985,
+	"KRW": 1486.82,
+	"MXN": 21.3652,
+	"MYR": 4.6811,
+	"NZD": 1.7927,
+	"PHP": 62.744,
+	"SGD": 1.4245,
+	"THB": 36.308,
+	"ZAR": 19.2138,
+}
+
+// Memory is an implementation of the RateProvider interface which
+// returns a fixed set of rates held in memory, it is mainly useful for testing
+type Memory struct {
+	mu    sync.Mutex
+	rates map[string]float64
+	err   error
+}
+
+// NewMemory creates a new in memory provider which returns the given rates
+func NewMemory(rates map[string]float64) *Memory {
+	m := &Memory{}
+	m.Set(rates)
+
+	return m
+}
+
+// Set replaces the rates returned by the provider
+func (m *Memory) Set(rates map[string]float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rates = copyRates(rates)
+}
+
+// Fail causes subsequent calls to Rates to return the given error,
+// passing nil restores normal behavior
+func (m *Memory) Fail(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.err = err
+}
+
+// Name returns the name of the provider
+func (m *Memory) Name() string {
+	return "memory"
+}
+
+// Rates returns a copy of the rates held by the provider
+func (m *Memory) Rates(ctx context.Context) (map[string]float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return copyRates(m.rates), nil
+}
+
+func copyRates(rates map[string]float64) map[string]float64 {
+	c := make(map[string]float64, len(rates))
+	for k, v := range rates {
+		c[k] = v
+	}
+
+	return c
+}
@@ -0,0 +1,76 @@
+package data
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// Convert converts the amount from the base to the destination currency using
+// exact decimal arithmetic, the result is rounded to the minor units of the
+// destination currency. The side of the client selects the rate of the named
+// profile, the bid when the client sells and the ask when the client buys. An
+// empty profile uses the mid rate. The rate used for the conversion is also returned
+func (e *ExchangeRates) Convert(base, dest, profile string, side Side, amount *big.Rat) (*big.Rat, float64, error) {
+	spread := new(big.Rat)
+	if profile != "" {
+		p, ok := e.profiles[profile]
+		if !ok {
+			return nil, 0, fmt.Errorf("spread profile %s not found", profile)
+		}
+
+		spread = p.spread(base, dest).fraction()
+	}
+
+	e.mu.RLock()
+	br, bok := e.rates[base]
+	dr, dok := e.rates[dest]
+	e.mu.RUnlock()
+
+	if !bok {
+		return nil, 0, fmt.Errorf("rate not found for currency %s", base)
+	}
+
+	if !dok {
+		return nil, 0, fmt.Errorf("rate not found for currency %s", dest)
+	}
+
+	// the margin is subtracted from the bid and added to the ask
+	if side == Buy {
+		spread.Neg(spread)
+	}
+
+	// amount * dest / base * (1 - spread)
+	rate := new(big.Rat).Quo(decimal(dr), decimal(br))
+	rate.Mul(rate, new(big.Rat).Sub(big.NewRat(1, 1), spread))
+
+	r := new(big.Rat).Mul(amount, rate)
+	f, _ := rate.Float64()
+
+	return Round(r, MinorUnits(dest)), f, nil
+}
+
+// Round rounds the number to the given number of decimal digits,
+// halves are rounded away from zero
+func Round(r *big.Rat, digits int) *big.Rat {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(digits)), nil)
+
+	// scale the number and add a half before truncating
+	n := new(big.Rat).Mul(new(big.Rat).Abs(r), new(big.Rat).SetInt(scale))
+	n.Add(n, big.NewRat(1, 2))
+
+	q := new(big.Int).Quo(n.Num(), n.Denom())
+	if r.Sign() < 0 {
+		q.Neg(q)
+	}
+
+	return new(big.Rat).SetFrac(q, scale)
+}
+
+// decimal returns the exact value of the shortest decimal representation of
+// the float, rates are published as decimals and this avoids carrying the
+// binary rounding error of the float into the conversion
+func decimal(f float64) *big.Rat {
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	return r
+}
@@ -0,0 +1,51 @@
+package data
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+func TestConvert(t *testing.T) {
+	tr, err := NewRates(hclog.NewNullLogger(), NewMemory(map[string]float64{"USD": 1.0895, "JPY": 162.85, "GBP": 0.83418}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		base     string
+		dest     string
+		amount   string
+		expected string
+	}{
+		{"EUR", "USD", "2.45", "2.67"},       // 2.669275
+		{"EUR", "USD", "-2.45", "-2.67"},     // -2.669275
+		{"EUR", "JPY", "1.99", "324"},        // 324.0715
+		{"GBP", "EUR", "100", "119.88"},      // 119.87820...
+		{"EUR", "USD", "0.000000001", "0"},   // smaller than a cent
+		{"USD", "USD", "1.005", "1.01"},      // halves round away from zero
+		{"EUR", "GBP", "3000000", "2502540"}, // large amounts are exact
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.base+tc.dest+tc.amount, func(t *testing.T) {
+			a, _ := new(big.Rat).SetString(tc.amount)
+
+			r, _, err := tr.Convert(tc.base, tc.dest, "", Sell, a)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			e, _ := new(big.Rat).SetString(tc.expected)
+			if r.Cmp(e) != 0 {
+				t.Fatalf("expected %s, got %s", tc.expected, r.FloatString(MinorUnits(tc.dest)))
+			}
+		})
+	}
+
+	_, _, err = tr.Convert("EUR", "XXX", "", Sell, big.NewRat(1, 1))
+	if err == nil {
+		t.Fatal("expected error for unknown currency")
+	}
+}
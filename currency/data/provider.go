@@ -0,0 +1,32 @@
+package data
+
+import (
+	"context"
+	"time"
+)
+
+// RateProvider defines the behavior for fetching exchange rates.
+// Rates are returned as the value of one EUR in each currency.
+// Implementations may be the ECB reference feed, a local file, an in memory fake, etc
+type RateProvider interface {
+	// Name identifies the provider, it is reported as the source of the rates
+	Name() string
+	Rates(ctx context.Context) (map[string]float64, error)
+}
+
+// HistoricalProvider defines the behavior for fetching the exchange rates
+// published on previous days, the rates are keyed by their publication date
+type HistoricalProvider interface {
+	// Name identifies the provider, it is reported as the source of the rates
+	Name() string
+	HistoricalRates(ctx context.Context) (map[time.Time]map[string]float64, error)
+}
+
+// PublishedProvider is implemented by providers whose rates are a daily
+// publication, such as the ECB reference rates. The rates are added to the
+// historical rates under their publication date when they are fetched
+type PublishedProvider interface {
+	// Published returns the publication date of the rates last returned by
+	// Rates, false is returned when the date is not known
+	Published() (time.Time, bool)
+}
@@ -1,113 +1,445 @@
 package data
 
 import (
-	"encoding/xml"
+	"context"
 	"fmt"
-	"math/rand"
-	"net/http"
-	"strconv"
+	"slices"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
+	"github.com/hnsia/go-nic/currency/metrics"
 )
 
+// ExchangeRates is a store of the current and historical exchange rates,
+// it is safe for concurrent use
 type ExchangeRates struct {
-	log   hclog.Logger
-	rates map[string]float64
+	log      hclog.Logger
+	provider RateProvider
+	metrics  *metrics.Metrics
+	profiles map[string]Profile
+	candles  *Candles
+	recorder *Recorder
+
+	// mu guards the fields below
+	mu           sync.RWMutex
+	rates        map[string]float64
+	published    map[string]float64            // rates as fetched, without simulated changes
+	history      map[string]map[string]float64 // rates keyed by publication date
+	days         []string                      // sorted publication dates in history
+	snapshotPath string
+	fetched      time.Time // time the current rates were fetched from the provider
+	stale        bool      // true when the rates were loaded from a snapshot
+	asOf         time.Time // time the current rates took effect
+	source       string    // source of the current rates
+	historyName  string    // name of the provider for the historical rates
+
+	updates chan struct{}
 }
 
-func NewRates(l hclog.Logger) (*ExchangeRates, error) {
-	er := &ExchangeRates{log: l, rates: map[string]float64{}}
+// Sources of the rates which are not a provider
+const (
+	SourceSnapshot   = "snapshot"
+	SourceSimulation = "simulation"
+)
+
+// Provenance describes when and where a rate was obtained
+type Provenance struct {
+	// AsOf is the time the rate took effect, this is the time the rates were
+	// fetched or simulated, or the publication date for historical rates
+	AsOf time.Time
+	// Source is the name of the provider, SourceSnapshot or SourceSimulation
+	Source string
+	// Stale is true when the rates were loaded from a snapshot because
+	// the provider could not be reached
+	Stale bool
+}
 
-	err := er.getRates()
+// Quote is an exchange rate along with its provenance
+type Quote struct {
+	// Rate is the mid rate
+	Rate float64
+	// Bid and Ask are the mid rate with the spread of the requested
+	// profile applied, they equal the mid rate when there is no spread
+	Bid float64
+	Ask float64
+	// Profile is the name of the spread profile applied to the bid and ask
+	Profile string
+	Provenance
+}
 
-	return er, err
+// newQuote returns a quote for the mid rate without a spread
+func newQuote(mid float64, p Provenance) Quote {
+	return Quote{Rate: mid, Bid: mid, Ask: mid, Provenance: p}
 }
 
+// Option configures optional behavior of ExchangeRates
+type Option func(*ExchangeRates)
+
+// WithSnapshot persists every successfully fetched set of rates to the file
+// at path. When the provider can not be reached at startup the last
+// snapshot is loaded instead and the rates are marked as stale
+func WithSnapshot(path string) Option {
+	return func(e *ExchangeRates) {
+		e.snapshotPath = path
+	}
+}
+
+// WithMetrics records the provider fetches and the age of the rates
+func WithMetrics(m *metrics.Metrics) Option {
+	return func(e *ExchangeRates) {
+		e.metrics = m
+	}
+}
+
+// NewRates creates a new ExchangeRates and fetches the initial rates from the given provider
+func NewRates(l hclog.Logger, p RateProvider, opts ...Option) (*ExchangeRates, error) {
+	er := &ExchangeRates{
+		log:       l,
+		provider:  p,
+		rates:     map[string]float64{},
+		published: map[string]float64{},
+		history:   map[string]map[string]float64{},
+		updates:   make(chan struct{}, 1),
+	}
+	for _, o := range opts {
+		o(er)
+	}
+
+	_, err := er.getRates(context.Background())
+	if err == nil || er.snapshotPath == "" {
+		return er, err
+	}
+
+	l.Error("Unable to fetch rates from provider, loading snapshot", "error", err, "path", er.snapshotPath)
+
+	s, serr := loadSnapshot(er.snapshotPath)
+	if serr != nil {
+		return er, fmt.Errorf("unable to fetch rates: %w, unable to load snapshot: %s", err, serr)
+	}
+
+	er.rates = s.Rates
+	er.published = copyRates(s.Rates)
+	er.fetched = s.Fetched
+	er.stale = true
+	er.asOf = s.Fetched
+	er.source = SourceSnapshot
+	er.candles.record(er.asOf, er.rates)
+	er.recordChange(KindSnapshot)
+	er.metrics.RatesFetched(er.fetched, true)
+
+	return er, nil
+}
+
+// Stale returns true when the rates were loaded from a snapshot because
+// the provider could not be reached
+func (e *ExchangeRates) Stale() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.stale
+}
+
+// Fetched returns the time the current rates were fetched from the provider
+func (e *ExchangeRates) Fetched() time.Time {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.fetched
+}
+
+// Provenance returns when and where the current rates were obtained
+func (e *ExchangeRates) Provenance() Provenance {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.provenance()
+}
+
+// provenance returns the provenance of the current rates, the caller must hold the lock
+func (e *ExchangeRates) provenance() Provenance {
+	return Provenance{AsOf: e.asOf, Source: e.source, Stale: e.stale}
+}
+
+// GetRate returns the current mid rate to convert from the base to the destination currency
 func (e *ExchangeRates) GetRate(base, dest string) (float64, error) {
+	q, err := e.GetQuote(base, dest, "")
+
+	return q.Rate, err
+}
+
+// GetQuote returns the current rate to convert from the base to the destination
+// currency along with the provenance of the rate. The bid and ask have the spread
+// of the named profile applied, an empty profile returns the mid rate on both sides
+func (e *ExchangeRates) GetQuote(base, dest, profile string) (Quote, error) {
+	var spread float64
+	if profile != "" {
+		p, ok := e.profiles[profile]
+		if !ok {
+			return Quote{}, fmt.Errorf("spread profile %s not found", profile)
+		}
+
+		spread, _ = p.spread(base, dest).fraction().Float64()
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
 	br, ok := e.rates[base]
 	if !ok {
-		return 0, fmt.Errorf("rate not found for currency %s", base)
+		return Quote{}, fmt.Errorf("rate not found for currency %s", base)
 	}
 
 	dr, ok := e.rates[dest]
 	if !ok {
-		return 0, fmt.Errorf("rate not found for currency %s", dest)
+		return Quote{}, fmt.Errorf("rate not found for currency %s", dest)
 	}
 
-	return dr / br, nil
+	q := newQuote(dr/br, e.provenance())
+	q.Bid = q.Rate * (1 - spread)
+	q.Ask = q.Rate * (1 + spread)
+	q.Profile = profile
+
+	return q, nil
+}
+
+// GetRates returns the current rates to convert from the base currency to each of the
+// destination currencies. All rates are taken from the same set of rates, errors are
+// returned for each destination so an unknown currency does not fail the other rates
+func (e *ExchangeRates) GetRates(base string, dests []string) ([]Quote, []error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	rates := make([]Quote, len(dests))
+	errs := make([]error, len(dests))
+	p := e.provenance()
+
+	br, ok := e.rates[base]
+	for i, d := range dests {
+		if !ok {
+			errs[i] = fmt.Errorf("rate not found for currency %s", base)
+			continue
+		}
+
+		dr, dok := e.rates[d]
+		if !dok {
+			errs[i] = fmt.Errorf("rate not found for currency %s", d)
+			continue
+		}
+
+		rates[i] = newQuote(dr/br, p)
+	}
+
+	return rates, errs
 }
 
-func (e *ExchangeRates) MonitorRates(interval time.Duration) chan struct{} {
-	ret := make(chan struct{})
+// LoadHistory fetches the rates for previous days from the given provider
+// and adds them to the historical rate table
+func (e *ExchangeRates) LoadHistory(p HistoricalProvider) error {
+	hr, err := p.HistoricalRates(context.Background())
+	if err != nil {
+		return err
+	}
 
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for t, rates := range hr {
+		rates["EUR"] = 1
+		e.addHistory(t, rates)
+	}
+
+	e.historyName = p.Name()
+	e.log.Info("Loaded historical rates", "days", len(hr))
+
+	return nil
+}
+
+// addHistory adds the rates published on the date of t to the historical rate
+// table, replacing any rates for the same date. The caller must hold the lock
+func (e *ExchangeRates) addHistory(t time.Time, rates map[string]float64) {
+	day := t.Format(time.DateOnly)
+
+	i, found := slices.BinarySearch(e.days, day)
+	if !found {
+		e.days = slices.Insert(e.days, i, day)
+	}
+
+	e.history[day] = rates
+}
+
+// GetHistoricalRate returns the rate which applied on the given date, the rate is
+// as of the date it was published. Rates are not published on weekends and holidays,
+// in this case the most recent rate published before the date is returned. An error
+// is returned for dates after the last known publication unless no rates were due
+// to be published since then
+func (e *ExchangeRates) GetHistoricalRate(base, dest string, date time.Time) (Quote, error) {
+	day := date.Format(time.DateOnly)
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	// find the last publication date on or before the requested date
+	i := sort.SearchStrings(e.days, day)
+	if i == len(e.days) || e.days[i] != day {
+		i--
+	}
+
+	if i < 0 {
+		return Quote{}, fmt.Errorf("no historical rates found for date %s", day)
+	}
+
+	published := e.days[i]
+	rates := e.history[published]
+	pt, _ := time.Parse(time.DateOnly, published)
+
+	// a later date only uses the last publication when it falls on the
+	// weekends and holidays directly after it
+	if i == len(e.days)-1 && day != published {
+		requested, _ := time.Parse(time.DateOnly, day)
+		for d := pt.AddDate(0, 0, 1); !d.After(requested); d = d.AddDate(0, 0, 1) {
+			if BusinessDay(d) {
+				return Quote{}, fmt.Errorf("no historical rates found for date %s, the last known publication is %s", day, published)
+			}
+		}
+	}
+
+	br, ok := rates[base]
+	if !ok {
+		return Quote{}, fmt.Errorf("rate not found for currency %s on %s", base, published)
+	}
+
+	dr, ok := rates[dest]
+	if !ok {
+		return Quote{}, fmt.Errorf("rate not found for currency %s on %s", dest, published)
+	}
+
+	return newQuote(dr/br, Provenance{AsOf: pt, Source: e.historyName}), nil
+}
+
+// Updates returns a channel which receives a message after the rates change,
+// notifications are coalesced so a slow reader receives a single message for
+// many changes
+func (e *ExchangeRates) Updates() <-chan struct{} {
+	return e.updates
+}
+
+// notify signals that the rates have changed without blocking
+func (e *ExchangeRates) notify() {
+	select {
+	case e.updates <- struct{}{}:
+	default:
+	}
+}
+
+// MonitorRates simulates fluctuations in the rates at the interval configured
+// for the simulator until the context is cancelled, listeners are notified
+// through Updates after every change
+func (e *ExchangeRates) MonitorRates(ctx context.Context, s *Simulator) {
 	go func() {
-		ticker := time.NewTicker(interval)
+		ticker := time.NewTicker(s.cfg.Interval)
+		defer ticker.Stop()
+
 		for {
 			select {
 			case <-ticker.C:
-				// just add a random difference to the rate and return it
-				// this simulates the fluctuations in currency rates
-				for k, v := range e.rates {
-					// change can be 10% of original value
-					change := (rand.Float64() / 10)
-					// is this a positive or negative change
-					direction := rand.Intn(1)
-
-					if direction == 0 {
-						// new value will be min 90% of old
-						change = 1 - change
-					} else {
-						// new value will be at max 110% of old
-						change = 1 + change
-					}
-
-					// modify the rate
-					e.rates[k] = v * change
-				}
-
-				// notify updates, this will block unless there is a listener on the other end
-				ret <- struct{}{}
+				e.Tick(s)
+			case <-ctx.Done():
+				return
 			}
 		}
 	}()
+}
+
+// Tick applies a single step of the simulation to the rates and notifies listeners
+func (e *ExchangeRates) Tick(s *Simulator) {
+	e.mu.Lock()
+	s.step(e.rates, e.published)
+	e.asOf = time.Now()
+	e.source = SourceSimulation
+	e.candles.record(e.asOf, e.rates)
+	e.recordChange(KindTick)
+	e.mu.Unlock()
+
+	e.notify()
+}
+
+// Refresh fetches the rates from the provider, listeners are notified through
+// Updates when the provider returned different rates. True is returned when the
+// rates changed
+func (e *ExchangeRates) Refresh(ctx context.Context) (bool, error) {
+	changed, err := e.getRates(ctx)
+	if changed {
+		e.notify()
+	}
 
-	return ret
+	return changed, err
 }
 
-func (e *ExchangeRates) getRates() error {
-	resp, err := http.DefaultClient.Get("https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml")
+// getRates fetches the rates from the provider, true is returned when they
+// differ from the rates previously fetched
+func (e *ExchangeRates) getRates(ctx context.Context) (bool, error) {
+	start := time.Now()
+	rates, err := e.provider.Rates(ctx)
+	e.metrics.ProviderFetch(time.Since(start), err)
 	if err != nil {
-		return nil
+		return false, err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("expected error code 200, got %d instead", resp.StatusCode)
+	// all rates are quoted against EUR
+	rates = copyRates(rates)
+	rates["EUR"] = 1
+
+	e.mu.Lock()
+	changed := false
+	for k, v := range rates {
+		if p, ok := e.published[k]; !ok || p != v {
+			changed = true
+		}
 	}
-	defer resp.Body.Close()
 
-	md := &Cubes{}
-	xml.NewDecoder(resp.Body).Decode(&md)
+	e.fetched = time.Now()
 
-	for _, c := range md.CubeData {
-		r, err := strconv.ParseFloat(c.Rate, 64)
-		if err != nil {
-			return err
+	// unchanged rates keep any simulated changes and their provenance, stale
+	// rates are replaced so the provider is reported as the source
+	if changed || e.stale {
+		for k, v := range rates {
+			e.rates[k] = v
+			e.published[k] = v
 		}
 
-		e.rates[c.Currency] = r
+		e.asOf = e.fetched
+		e.source = e.provider.Name()
+		e.candles.record(e.asOf, e.rates)
+		e.recordChange(KindSnapshot)
 	}
 
-	e.rates["EUR"] = 1
+	// daily publications are kept so the rates can be looked up by date later
+	if pp, ok := e.provider.(PublishedProvider); ok {
+		if published, ok := pp.Published(); ok {
+			e.addHistory(published, copyRates(rates))
+			if e.historyName == "" {
+				e.historyName = e.provider.Name()
+			}
+		}
+	}
 
-	return nil
-}
+	e.stale = false
+	s := &Snapshot{Rates: copyRates(e.published), Fetched: e.fetched}
+	e.mu.Unlock()
 
-type Cubes struct {
-	CubeData []Cube `xml:"Cube>Cube>Cube"`
-}
+	e.metrics.RatesFetched(s.Fetched, false)
+
+	if e.snapshotPath == "" {
+		return changed, nil
+	}
+
+	err = saveSnapshot(e.snapshotPath, s)
+	if err != nil {
+		// a failed snapshot does not affect the current rates
+		e.log.Error("Unable to save rate snapshot", "error", err, "path", e.snapshotPath)
+	}
 
-type Cube struct {
-	Currency string `xml:"currency,attr"`
-	Rate     string `xml:"rate,attr"`
+	return changed, nil
 }
@@ -1,17 +1,392 @@
 package data
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
 )
 
+const ecbDaily = `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<Cube>
+		<Cube time='2024-10-15'>
+			<Cube currency='USD' rate='1.0895'/>
+			<Cube currency='JPY' rate='162.85'/>
+			<Cube currency='GBP' rate='0.83418'/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+func newECBServer(t *testing.T) *httptest.Server {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, ecbDaily)
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
 func TestNewRates(t *testing.T) {
-	tr, err := NewRates(hclog.Default())
+	s := newECBServer(t)
+
+	tr, err := NewRates(hclog.Default(), NewECB(s.URL))
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	fmt.Printf("Rates %#v", tr.rates)
+
+	r, err := tr.GetRate("EUR", "USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r != 1.0895 {
+		t.Fatalf("expected rate 1.0895, got %f", r)
+	}
+}
+
+func TestNewRatesReturnsProviderError(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer s.Close()
+
+	_, err := NewRates(hclog.Default(), NewECB(s.URL))
+	if err == nil {
+		t.Fatal("expected error when provider is unavailable")
+	}
+}
+
+func TestFileProvider(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"rates.json": `{"USD": 1.0895, "GBP": 0.83418}`,
+		"rates.csv":  "currency,rate\nUSD,1.0895\ngbp, 0.83418\n",
+	}
+
+	for name, contents := range files {
+		t.Run(name, func(t *testing.T) {
+			p := filepath.Join(dir, name)
+			err := os.WriteFile(p, []byte(contents), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			fp, err := NewFile(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tr, err := NewRates(hclog.Default(), fp)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			r, err := tr.GetRate("USD", "GBP")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			usd, gbp := 1.0895, 0.83418
+			if r != gbp/usd {
+				t.Fatalf("unexpected rate %f", r)
+			}
+		})
+	}
+}
+
+func TestMemoryProvider(t *testing.T) {
+	tr, err := NewRates(hclog.Default(), NewMemory(SampleRates))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = tr.GetRate("EUR", "XXX")
+	if err == nil {
+		t.Fatal("expected error for unknown currency")
+	}
+}
+
+const ecbHistory = `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<Cube>
+		<Cube time="2024-10-14">
+			<Cube currency="USD" rate="1.0911"/>
+			<Cube currency="GBP" rate="0.8351"/>
+		</Cube>
+		<Cube time="2024-10-11">
+			<Cube currency="USD" rate="1.0935"/>
+			<Cube currency="GBP" rate="0.8372"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+func TestGetHistoricalRate(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "eurofxref-hist.xml")
+	err := os.WriteFile(p, []byte(ecbHistory), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tr, err := NewRates(hclog.Default(), NewMemory(SampleRates))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = tr.LoadHistory(NewECB("file://" + p))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		date      string
+		rate      float64
+		published string
+	}{
+		{"2024-10-11", 1.0935, "2024-10-11"},
+		{"2024-10-13", 1.0935, "2024-10-11"}, // sunday uses the friday rate
+		{"2024-10-14", 1.0911, "2024-10-14"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.date, func(t *testing.T) {
+			d, _ := time.Parse(time.DateOnly, tc.date)
+
+			q, err := tr.GetHistoricalRate("EUR", "USD", d)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if q.Rate != tc.rate {
+				t.Fatalf("expected rate %f, got %f", tc.rate, q.Rate)
+			}
+
+			if q.AsOf.Format(time.DateOnly) != tc.published {
+				t.Fatalf("expected publication date %s, got %s", tc.published, q.AsOf.Format(time.DateOnly))
+			}
+
+			if q.Source != "ecb" {
+				t.Fatalf("expected source ecb, got %s", q.Source)
+			}
+		})
+	}
+
+	_, err = tr.GetHistoricalRate("EUR", "USD", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err == nil {
+		t.Fatal("expected error for date before history")
+	}
+
+	// rates were due on the business days after the last publication
+	for _, d := range []string{"2024-10-15", "2024-12-25"} {
+		date, _ := time.Parse(time.DateOnly, d)
+
+		_, err = tr.GetHistoricalRate("EUR", "USD", date)
+		if err == nil {
+			t.Fatalf("expected error for %s after the last publication", d)
+		}
+	}
+}
+
+func TestFetchedRatesAreAddedToHistory(t *testing.T) {
+	var mu sync.Mutex
+	day, usd := "2024-10-11", "1.0935"
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		fmt.Fprintf(w, `<Envelope><Cube><Cube time="%s"><Cube currency="USD" rate="%s"/></Cube></Cube></Envelope>`, day, usd)
+	}))
+	defer s.Close()
+
+	tr, err := NewRates(hclog.NewNullLogger(), NewChain(hclog.NewNullLogger(), nil, Link{Provider: NewECB(s.URL)}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rate := func(d string) (float64, error) {
+		date, _ := time.Parse(time.DateOnly, d)
+		q, err := tr.GetHistoricalRate("EUR", "USD", date)
+
+		return q.Rate, err
+	}
+
+	// the weekend after friday's publication uses the friday rate
+	if r, err := rate("2024-10-13"); err != nil || r != 1.0935 {
+		t.Fatalf("expected the friday rate, got %f %v", r, err)
+	}
+
+	// monday's rates have not been fetched yet
+	if _, err := rate("2024-10-14"); err == nil {
+		t.Fatal("expected error for a date after the last publication")
+	}
+
+	mu.Lock()
+	day, usd = "2024-10-14", "1.0911"
+	mu.Unlock()
+
+	_, err = tr.Refresh(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for d, expected := range map[string]float64{"2024-10-11": 1.0935, "2024-10-14": 1.0911} {
+		if r, err := rate(d); err != nil || r != expected {
+			t.Fatalf("expected rate %f on %s, got %f %v", expected, d, r, err)
+		}
+	}
+}
+
+func TestNewRatesLoadsSnapshotWhenProviderFails(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "snapshot.json")
+	mp := NewMemory(SampleRates)
+
+	tr, err := NewRates(hclog.Default(), mp, WithSnapshot(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tr.Stale() {
+		t.Fatal("expected fresh rates")
+	}
+
+	mp.Fail(fmt.Errorf("provider unavailable"))
+
+	tr, err = NewRates(hclog.Default(), mp, WithSnapshot(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !tr.Stale() {
+		t.Fatal("expected stale rates from snapshot")
+	}
+
+	q, err := tr.GetQuote("EUR", "USD", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if q.Rate != SampleRates["USD"] {
+		t.Fatalf("expected rate %f, got %f", SampleRates["USD"], q.Rate)
+	}
+
+	if q.Source != SourceSnapshot || !q.Stale || !q.AsOf.Equal(tr.Fetched()) {
+		t.Fatalf("expected stale quote from snapshot, got %+v", q.Provenance)
+	}
+}
+
+func TestQuoteProvenance(t *testing.T) {
+	tr, err := NewRates(hclog.Default(), NewMemory(SampleRates))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q, err := tr.GetQuote("EUR", "USD", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if q.Source != "memory" || q.Stale || q.AsOf.IsZero() {
+		t.Fatalf("expected fresh quote from the provider, got %+v", q.Provenance)
+	}
+
+	tr.Tick(NewSimulator(Simulation{Seed: 1, MaxDrift: 0.01}))
+
+	q, err = tr.GetQuote("EUR", "USD", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if q.Source != SourceSimulation || q.AsOf.Before(tr.Fetched()) {
+		t.Fatalf("expected simulated quote, got %+v", q.Provenance)
+	}
+}
+
+func TestNewRatesFailsWithoutSnapshot(t *testing.T) {
+	mp := NewMemory(SampleRates)
+	mp.Fail(fmt.Errorf("provider unavailable"))
+
+	_, err := NewRates(hclog.Default(), mp, WithSnapshot(filepath.Join(t.TempDir(), "missing.json")))
+	if err == nil {
+		t.Fatal("expected error when provider and snapshot are unavailable")
+	}
+}
+
+func TestConcurrentGetRateAndMonitor(t *testing.T) {
+	tr, err := NewRates(hclog.NewNullLogger(), NewMemory(SampleRates))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tr.MonitorRates(ctx, NewSimulator(Simulation{Seed: 1, Interval: time.Millisecond, MaxDrift: 0.01}))
+
+	done := make(chan struct{})
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				_, err := tr.GetRate("USD", "GBP")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 5; i++ {
+		<-tr.Updates()
+	}
+
+	close(done)
+	wg.Wait()
+}
+
+func TestMonitorRatesStopsWhenCancelled(t *testing.T) {
+	tr, err := NewRates(hclog.NewNullLogger(), NewMemory(SampleRates))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tr.MonitorRates(ctx, NewSimulator(Simulation{Seed: 1, Interval: time.Millisecond, MaxDrift: 0.01}))
+
+	<-tr.Updates()
+	cancel()
+
+	// allow a tick in progress to finish before clearing the notification
+	time.Sleep(10 * time.Millisecond)
+	select {
+	case <-tr.Updates():
+	default:
+	}
+
+	select {
+	case <-tr.Updates():
+		t.Fatal("expected no updates after the context is cancelled")
+	case <-time.After(20 * time.Millisecond):
+	}
 }
@@ -0,0 +1,205 @@
+package data
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// SourceReplay is the source of rates replayed from a recording
+const SourceReplay = "replay"
+
+// Kinds of recorded rate changes
+const (
+	// KindSnapshot is a complete set of rates from a provider or a snapshot file
+	KindSnapshot = "snapshot"
+	// KindTick is a simulated change to the rates
+	KindTick = "tick"
+)
+
+// Record is a single change to the rates written to a recording
+type Record struct {
+	Time   time.Time          `json:"time"`
+	Kind   string             `json:"kind"`
+	Source string             `json:"source"`
+	Rates  map[string]float64 `json:"rates"`
+}
+
+// Recorder appends every change to the rates to a file as newline
+// delimited JSON, it is safe for concurrent use
+type Recorder struct {
+	mu sync.Mutex
+	f  *os.File
+	w  *bufio.Writer
+}
+
+// NewRecorder opens the recording at path, new records are appended to the file
+func NewRecorder(path string) (*Recorder, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open recording: %w", err)
+	}
+
+	return &Recorder{f: f, w: bufio.NewWriter(f)}, nil
+}
+
+// WithRecorder writes every change to the rates to the recorder
+func WithRecorder(r *Recorder) Option {
+	return func(e *ExchangeRates) {
+		e.recorder = r
+	}
+}
+
+// record writes the record as a single line, the line is flushed so a crash
+// loses at most the record being written. Nothing is recorded for a nil recorder
+func (r *Recorder) record(rec Record) error {
+	if r == nil {
+		return nil
+	}
+
+	d, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("unable to encode record: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, err = r.w.Write(append(d, '\n'))
+	if err != nil {
+		return fmt.Errorf("unable to write record: %w", err)
+	}
+
+	return r.w.Flush()
+}
+
+// Close flushes and closes the recording
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err := r.w.Flush()
+	if err != nil {
+		r.f.Close()
+		return err
+	}
+
+	return r.f.Close()
+}
+
+// LoadRecording reads the records written by a Recorder
+func LoadRecording(path string) ([]Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open recording: %w", err)
+	}
+	defer f.Close()
+
+	records := []Record{}
+	d := json.NewDecoder(f)
+	for d.More() {
+		rec := Record{}
+		err := d.Decode(&rec)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode record %d: %w", len(records)+1, err)
+		}
+
+		records = append(records, rec)
+	}
+
+	if len(records) == 0 {
+		return nil, fmt.Errorf("recording %s contains no records", path)
+	}
+
+	return records, nil
+}
+
+// Replay is an implementation of the RateProvider interface which returns the
+// rates from a recording. The provider returns the rates of the first record,
+// the changes in the recording are applied to ExchangeRates by ReplayRates
+type Replay struct {
+	records []Record
+	speed   float64
+}
+
+// NewReplay creates a provider for the recording at path, speed is the multiple
+// of the original speed the records are replayed at, e.g. 10 replays ten times faster
+func NewReplay(path string, speed float64) (*Replay, error) {
+	if speed <= 0 {
+		return nil, fmt.Errorf("replay speed must be greater than 0")
+	}
+
+	records, err := LoadRecording(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Replay{records: records, speed: speed}, nil
+}
+
+// Name returns the name of the provider
+func (r *Replay) Name() string {
+	return SourceReplay
+}
+
+// Rates returns the rates from the first record
+func (r *Replay) Rates(ctx context.Context) (map[string]float64, error) {
+	return copyRates(r.records[0].Rates), nil
+}
+
+// recordChange writes the current rates to the recorder, the caller must hold the lock
+func (e *ExchangeRates) recordChange(kind string) {
+	err := e.recorder.record(Record{Time: e.asOf, Kind: kind, Source: e.source, Rates: e.rates})
+	if err != nil {
+		// a failed recording does not affect the current rates
+		e.log.Error("Unable to record rates", "error", err)
+	}
+}
+
+// ReplayRates applies the records to the rates with the same delays between
+// them as when they were recorded, divided by the speed of the replay. The first
+// record is applied immediately. After each record is applied it is passed to
+// publish and the next record is not applied until publish returns, so a listener
+// which delivers the rates from publish receives every record in order at any
+// speed. Listeners of Updates are not notified as updates are coalesced. The
+// replay stops at the end of the recording or when the context is cancelled
+func (e *ExchangeRates) ReplayRates(ctx context.Context, r *Replay, publish func(Record)) {
+	go func() {
+		prev := r.records[0].Time
+
+		for i, rec := range r.records {
+			wait := time.Duration(float64(rec.Time.Sub(prev)) / r.speed)
+			prev = rec.Time
+
+			err := sleep(ctx, wait)
+			if err != nil {
+				return
+			}
+
+			e.log.Debug("Replaying record", "record", i+1, "time", rec.Time, "kind", rec.Kind)
+			e.apply(rec)
+			publish(rec)
+		}
+
+		e.log.Info("Replay finished", "records", len(r.records))
+	}()
+}
+
+// apply replaces the rates with the recorded rates, the rates take effect at the recorded time
+func (e *ExchangeRates) apply(rec Record) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for k, v := range rec.Rates {
+		e.rates[k] = v
+	}
+
+	e.asOf = rec.Time
+	e.source = SourceReplay
+	e.candles.record(e.asOf, e.rates)
+	e.recordChange(rec.Kind)
+}
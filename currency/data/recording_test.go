@@ -0,0 +1,200 @@
+package data
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// writeRecording writes the records to a recording in a temporary directory
+func writeRecording(t *testing.T, records ...Record) string {
+	path := filepath.Join(t.TempDir(), "recording.ndjson")
+
+	r, err := NewRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, rec := range records {
+		err := r.record(rec)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = r.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestRecorderWritesEveryChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recording.ndjson")
+
+	r, err := NewRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tr, err := NewRates(hclog.NewNullLogger(), NewMemory(map[string]float64{"USD": 1.1}), WithRecorder(r))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sim := NewSimulator(Simulation{Seed: 1, MaxDrift: 0.01})
+	tr.Tick(sim)
+	tr.Tick(sim)
+
+	err = r.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := LoadRecording(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records, got %v", records)
+	}
+
+	if rec := records[0]; rec.Kind != KindSnapshot || rec.Source != "memory" || rec.Rates["USD"] != 1.1 {
+		t.Fatalf("expected the fetched rates first, got %v", rec)
+	}
+
+	rate, _ := tr.GetRate("EUR", "USD")
+	if rec := records[2]; rec.Kind != KindTick || rec.Source != SourceSimulation || rec.Rates["USD"] != rate {
+		t.Fatalf("expected the last tick to have the current rate %f, got %v", rate, rec)
+	}
+
+	// a second recorder appends to the recording
+	path2 := writeRecording(t, records...)
+	r, err = NewRecorder(path2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.record(Record{Time: time.Now(), Kind: KindTick, Source: SourceSimulation, Rates: map[string]float64{"USD": 1}})
+	r.Close()
+
+	appended, err := LoadRecording(path2)
+	if err != nil || len(appended) != 4 {
+		t.Fatalf("expected 4 records, got %d %v", len(appended), err)
+	}
+}
+
+func TestLoadRecordingRejectsInvalidFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	empty := filepath.Join(dir, "empty.ndjson")
+	os.WriteFile(empty, nil, 0644)
+
+	_, err := LoadRecording(empty)
+	if err == nil {
+		t.Fatal("expected an error for an empty recording")
+	}
+
+	corrupt := filepath.Join(dir, "corrupt.ndjson")
+	os.WriteFile(corrupt, []byte(`{"kind":"tick","rates":{"USD":1.1}}`+"\n"+`{"kind":`), 0644)
+
+	_, err = LoadRecording(corrupt)
+	if err == nil {
+		t.Fatal("expected an error for a corrupt recording")
+	}
+
+	_, err = NewReplay(empty, 0)
+	if err == nil {
+		t.Fatal("expected an error for a replay speed of 0")
+	}
+}
+
+func TestReplayRates(t *testing.T) {
+	start := time.Date(2024, 10, 15, 12, 0, 0, 0, time.UTC)
+	path := writeRecording(t,
+		Record{Time: start, Kind: KindSnapshot, Source: "ecb", Rates: map[string]float64{"EUR": 1, "USD": 1.1}},
+		Record{Time: start.Add(time.Second), Kind: KindTick, Source: SourceSimulation, Rates: map[string]float64{"EUR": 1, "USD": 1.2}},
+		Record{Time: start.Add(2 * time.Second), Kind: KindTick, Source: SourceSimulation, Rates: map[string]float64{"EUR": 1, "USD": 1.3}},
+	)
+
+	// replay the two seconds of the recording in 100ms
+	rp, err := NewReplay(path, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tr, err := NewRates(hclog.NewNullLogger(), rp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r, _ := tr.GetRate("EUR", "USD"); r != 1.1 {
+		t.Fatalf("expected the rate from the first record, got %f", r)
+	}
+
+	// every record is published with the rates it applied
+	published := make(chan float64)
+	begin := time.Now()
+	tr.ReplayRates(context.Background(), rp, func(rec Record) {
+		r, _ := tr.GetRate("EUR", "USD")
+		published <- r
+	})
+
+	for _, expected := range []float64{1.1, 1.2, 1.3} {
+		select {
+		case r := <-published:
+			if r != expected {
+				t.Fatalf("expected the replayed rate %f, got %f", expected, r)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the replay")
+		}
+	}
+
+	if d := time.Since(begin); d < 100*time.Millisecond {
+		t.Fatalf("expected the replay to take at least 100ms, took %s", d)
+	}
+
+	// the replayed rates take effect at the recorded time
+	p := tr.Provenance()
+	if p.Source != SourceReplay || !p.AsOf.Equal(start.Add(2*time.Second)) {
+		t.Fatalf("unexpected provenance %v", p)
+	}
+}
+
+func TestReplayStopsWhenCancelled(t *testing.T) {
+	start := time.Date(2024, 10, 15, 12, 0, 0, 0, time.UTC)
+	path := writeRecording(t,
+		Record{Time: start, Kind: KindSnapshot, Rates: map[string]float64{"USD": 1.1}},
+		Record{Time: start.Add(time.Hour), Kind: KindTick, Rates: map[string]float64{"USD": 1.2}},
+	)
+
+	rp, err := NewReplay(path, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tr, err := NewRates(hclog.NewNullLogger(), rp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	published := make(chan Record, 2)
+	tr.ReplayRates(ctx, rp, func(rec Record) { published <- rec })
+
+	// the first record is applied immediately
+	<-published
+	cancel()
+
+	time.Sleep(10 * time.Millisecond)
+	if r, _ := tr.GetRate("EUR", "USD"); r != 1.1 {
+		t.Fatalf("expected the replay to stop before the second record, got %f", r)
+	}
+}
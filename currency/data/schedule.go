@@ -0,0 +1,247 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	// the publication time zone is loaded in containers without a zoneinfo database
+	_ "time/tzdata"
+)
+
+// ECBPublishTime is the time of day after which the ECB reference rates
+// have been published, the rates are published at around 16:00 CET
+const ECBPublishTime = 16*time.Hour + 15*time.Minute
+
+// ECBTimeZone is the time zone of the ECB publication time
+const ECBTimeZone = "Europe/Berlin"
+
+// Schedule configures when the rates are refreshed from the provider. Refreshes
+// which fall on weekends and TARGET holidays, when no rates are published, are
+// skipped
+type Schedule struct {
+	// Interval refreshes the rates at a fixed interval, when 0 the rates
+	// are refreshed once a day at PublishTime
+	Interval time.Duration
+	// PublishTime is the time of day in Location at which the rates are
+	// refreshed, ECBPublishTime is used when 0
+	PublishTime time.Duration
+	// Location is the time zone for PublishTime and the business days,
+	// ECBTimeZone is used when nil
+	Location *time.Location
+	// Retry is the wait before a failed or outdated refresh is tried again,
+	// retries stop at the next scheduled refresh. 0 disables retries
+	Retry time.Duration
+}
+
+// location returns the time zone of the schedule
+func (s Schedule) location() *time.Location {
+	if s.Location != nil {
+		return s.Location
+	}
+
+	l, err := time.LoadLocation(ECBTimeZone)
+	if err != nil {
+		// tzdata is embedded so this is not expected, CET without summer time is close enough
+		return time.FixedZone("CET", 60*60)
+	}
+
+	return l
+}
+
+// Next returns the time of the next refresh after the given time
+func (s Schedule) Next(after time.Time) time.Time {
+	loc := s.location()
+
+	if s.Interval > 0 {
+		next := after.Add(s.Interval).In(loc)
+
+		// resume at the start of the next business day
+		for !BusinessDay(next) {
+			y, m, d := next.Date()
+			next = time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+		}
+
+		return next
+	}
+
+	publish := s.publishTime()
+
+	// the publication time is added to midnight as a wall clock time
+	// so it is correct on the days summer time starts and ends
+	day := after.In(loc)
+	for {
+		y, m, d := day.Date()
+		next := time.Date(y, m, d, 0, 0, 0, int(publish), loc)
+
+		if next.After(after) && BusinessDay(next) {
+			return next
+		}
+
+		day = time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+	}
+}
+
+// LastPublication returns the date of the latest publication which is due at
+// the given time, the latest business day whose PublishTime has passed. The
+// date is midnight UTC like the publication dates of the providers
+func (s Schedule) LastPublication(at time.Time) time.Time {
+	loc := s.location()
+	publish := s.publishTime()
+
+	day := at.In(loc)
+	for {
+		y, m, d := day.Date()
+		published := time.Date(y, m, d, 0, 0, 0, int(publish), loc)
+
+		if !published.After(at) && BusinessDay(published) {
+			return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+		}
+
+		day = time.Date(y, m, d-1, 0, 0, 0, 0, loc)
+	}
+}
+
+// publishTime returns the time of day the rates are published
+func (s Schedule) publishTime() time.Duration {
+	if s.PublishTime == 0 {
+		return ECBPublishTime
+	}
+
+	return s.PublishTime
+}
+
+// BusinessDay returns true when the ECB publishes rates on the date of t, rates
+// are published on weekdays other than TARGET holidays
+func BusinessDay(t time.Time) bool {
+	switch t.Weekday() {
+	case time.Saturday, time.Sunday:
+		return false
+	}
+
+	return !TargetHoliday(t)
+}
+
+// TargetHoliday returns true when the date of t is a TARGET closing day, New
+// Year's Day, Good Friday, Easter Monday, 1 May, Christmas Day or 26 December
+func TargetHoliday(t time.Time) bool {
+	y, m, d := t.Date()
+
+	switch {
+	case m == time.January && d == 1,
+		m == time.May && d == 1,
+		m == time.December && (d == 25 || d == 26):
+		return true
+	}
+
+	em, ed := easter(y)
+	easterSunday := time.Date(y, em, ed, 0, 0, 0, 0, time.UTC)
+	date := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+	return date.Equal(easterSunday.AddDate(0, 0, -2)) || date.Equal(easterSunday.AddDate(0, 0, 1))
+}
+
+// easter returns the month and day of Easter Sunday in the Gregorian
+// calendar using the anonymous Gregorian algorithm
+func easter(y int) (time.Month, int) {
+	a := y % 19
+	b := y / 100
+	c := y % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+
+	return time.Month(month), day
+}
+
+// RefreshRates fetches the rates from the provider at the times in the schedule
+// until the context is cancelled. Rates loaded from a snapshot or older than the
+// latest publication are retried without waiting for the first scheduled refresh.
+// Listeners are notified through Updates only when the provider returned
+// different rates
+func (e *ExchangeRates) RefreshRates(ctx context.Context, s Schedule) {
+	go func() {
+		if s.Retry > 0 && (e.Stale() || e.outdated(s) != nil) {
+			e.log.Info("Rates are out of date, retrying", "at", time.Now().Add(s.Retry))
+
+			err := sleep(ctx, s.Retry)
+			if err != nil {
+				return
+			}
+
+			e.refresh(ctx, s)
+		}
+
+		for {
+			next := s.Next(time.Now())
+			e.log.Info("Scheduled rate refresh", "at", next)
+
+			err := sleep(ctx, time.Until(next))
+			if err != nil {
+				return
+			}
+
+			e.refresh(ctx, s)
+		}
+	}()
+}
+
+// refresh fetches the rates from the provider, a failed fetch or a fetch which
+// returned an outdated publication, e.g. when the ECB publishes late, is retried
+// at the retry interval of the schedule until it succeeds or the next refresh is due
+func (e *ExchangeRates) refresh(ctx context.Context, s Schedule) {
+	for {
+		changed, err := e.Refresh(ctx)
+		if err == nil {
+			err = e.outdated(s)
+		}
+
+		if err == nil {
+			e.log.Info("Refreshed rates", "changed", changed)
+			return
+		}
+
+		retry := time.Now().Add(s.Retry)
+		if s.Retry <= 0 || !retry.Before(s.Next(time.Now())) {
+			e.log.Error("Unable to refresh rates", "error", err)
+			return
+		}
+
+		e.log.Error("Unable to refresh rates, retrying", "error", err, "at", retry)
+
+		err = sleep(ctx, s.Retry)
+		if err != nil {
+			return
+		}
+	}
+}
+
+// outdated returns an error when the provider returned rates which were published
+// before the latest publication that is due, providers which do not report their
+// publication date are never outdated
+func (e *ExchangeRates) outdated(s Schedule) error {
+	pp, ok := e.provider.(PublishedProvider)
+	if !ok {
+		return nil
+	}
+
+	published, ok := pp.Published()
+	if !ok {
+		return nil
+	}
+
+	if due := s.LastPublication(time.Now()); published.Before(due) {
+		return fmt.Errorf("provider returned the rates published on %s, expected %s", published.Format(time.DateOnly), due.Format(time.DateOnly))
+	}
+
+	return nil
+}
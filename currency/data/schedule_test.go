@@ -0,0 +1,338 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+func TestTargetHoliday(t *testing.T) {
+	holidays := []string{"2024-01-01", "2024-03-29", "2024-04-01", "2024-05-01", "2025-04-18", "2025-04-21", "2025-12-25", "2025-12-26"}
+	for _, d := range holidays {
+		date, _ := time.Parse(time.DateOnly, d)
+		if !TargetHoliday(date) || BusinessDay(date) {
+			t.Fatalf("expected %s to be a holiday", d)
+		}
+	}
+
+	workdays := []string{"2024-03-28", "2024-04-02", "2025-04-17", "2025-12-24", "2025-12-31"}
+	for _, d := range workdays {
+		date, _ := time.Parse(time.DateOnly, d)
+		if TargetHoliday(date) || !BusinessDay(date) {
+			t.Fatalf("expected %s to be a business day", d)
+		}
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	berlin, err := time.LoadLocation(ECBTimeZone)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	at := func(s string) time.Time {
+		v, err := time.ParseInLocation(time.DateTime, s, berlin)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return v
+	}
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		after    string
+		next     string
+	}{
+		{"before publication", Schedule{}, "2024-10-17 10:00:00", "2024-10-17 16:15:00"},
+		{"after publication", Schedule{}, "2024-10-17 16:15:00", "2024-10-18 16:15:00"},
+		{"weekend", Schedule{}, "2024-10-18 17:00:00", "2024-10-21 16:15:00"},
+		{"easter", Schedule{}, "2025-04-17 17:00:00", "2025-04-22 16:15:00"},
+		{"publish time", Schedule{PublishTime: 16 * time.Hour}, "2024-10-17 10:00:00", "2024-10-17 16:00:00"},
+		{"interval", Schedule{Interval: time.Hour}, "2024-10-17 10:30:00", "2024-10-17 11:30:00"},
+		{"interval over the weekend", Schedule{Interval: time.Hour}, "2024-10-18 23:30:00", "2024-10-21 00:00:00"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			next := tc.schedule.Next(at(tc.after))
+			if !next.Equal(at(tc.next)) {
+				t.Fatalf("expected %s, got %s", tc.next, next.In(berlin))
+			}
+		})
+	}
+}
+
+func TestScheduleLastPublication(t *testing.T) {
+	berlin, err := time.LoadLocation(ECBTimeZone)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		at        string
+		published string
+	}{
+		{"before publication", "2024-10-17 10:00:00", "2024-10-16"},
+		{"after publication", "2024-10-17 16:15:00", "2024-10-17"},
+		{"weekend", "2024-10-20 17:00:00", "2024-10-18"},
+		{"monday morning", "2024-10-21 09:00:00", "2024-10-18"},
+		{"easter", "2025-04-22 10:00:00", "2025-04-17"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			at, err := time.ParseInLocation(time.DateTime, tc.at, berlin)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			published := Schedule{}.LastPublication(at)
+			if published.Format(time.DateOnly) != tc.published || published.Location() != time.UTC {
+				t.Fatalf("expected %s, got %s", tc.published, published)
+			}
+		})
+	}
+}
+
+func TestScheduleFollowsSummerTime(t *testing.T) {
+	// summer time ends on 27 October 2024, 16:15 CEST is 14:15 UTC and 16:15 CET is 15:15 UTC
+	s := Schedule{}
+
+	next := s.Next(time.Date(2024, 10, 25, 12, 0, 0, 0, time.UTC))
+	if !next.Equal(time.Date(2024, 10, 25, 14, 15, 0, 0, time.UTC)) {
+		t.Fatalf("expected 14:15 UTC, got %s", next.UTC())
+	}
+
+	next = s.Next(time.Date(2024, 10, 28, 12, 0, 0, 0, time.UTC))
+	if !next.Equal(time.Date(2024, 10, 28, 15, 15, 0, 0, time.UTC)) {
+		t.Fatalf("expected 15:15 UTC, got %s", next.UTC())
+	}
+}
+
+func TestRefreshOnlyNotifiesChanges(t *testing.T) {
+	mp := NewMemory(map[string]float64{"USD": 1.1, "GBP": 0.8})
+
+	tr, err := NewRates(hclog.NewNullLogger(), mp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// simulated changes are kept when the provider rates have not changed
+	tr.Tick(NewSimulator(Simulation{Seed: 1, MaxDrift: 0.01}))
+	<-tr.Updates()
+
+	simulated, _ := tr.GetRate("EUR", "USD")
+
+	changed, err := tr.Refresh(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r, _ := tr.GetRate("EUR", "USD"); changed || r != simulated {
+		t.Fatalf("expected unchanged rates to keep the simulated rate %f, got %f", simulated, r)
+	}
+
+	select {
+	case <-tr.Updates():
+		t.Fatal("expected no update when the rates did not change")
+	default:
+	}
+
+	// new rates from the provider replace the simulated rates
+	mp.Set(map[string]float64{"USD": 1.2, "GBP": 0.8})
+
+	changed, err = tr.Refresh(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r, _ := tr.GetRate("EUR", "USD"); !changed || r != 1.2 {
+		t.Fatalf("expected the refreshed rate 1.2, got %f", r)
+	}
+
+	select {
+	case <-tr.Updates():
+	default:
+		t.Fatal("expected an update when the rates changed")
+	}
+
+	if p := tr.Provenance(); p.Source != "memory" {
+		t.Fatalf("expected the provider as the source, got %s", p.Source)
+	}
+}
+
+func TestRefreshRatesStopsWhenCancelled(t *testing.T) {
+	tr, err := NewRates(hclog.NewNullLogger(), NewMemory(SampleRates))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tr.RefreshRates(ctx, Schedule{Interval: time.Millisecond})
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	// the rates do not change so listeners are never notified
+	select {
+	case <-tr.Updates():
+		t.Fatal("expected no update for unchanged rates")
+	default:
+	}
+}
+
+func TestRefreshRetriesFailures(t *testing.T) {
+	mp := NewMemory(map[string]float64{"USD": 1.1})
+
+	tr, err := NewRates(hclog.NewNullLogger(), mp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mp.Fail(errors.New("unavailable"))
+	mp.Set(map[string]float64{"USD": 1.2})
+
+	done := make(chan struct{})
+	go func() {
+		tr.refresh(context.Background(), Schedule{Interval: time.Hour, Retry: 5 * time.Millisecond})
+		close(done)
+	}()
+
+	// the provider recovers after the first attempts have failed
+	time.Sleep(20 * time.Millisecond)
+	mp.Fail(nil)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the refresh to be retried")
+	}
+
+	if r, _ := tr.GetRate("EUR", "USD"); r != 1.2 {
+		t.Fatalf("expected the retried refresh to fetch 1.2, got %f", r)
+	}
+}
+
+func TestRefreshStopsRetryingAtNextRefresh(t *testing.T) {
+	mp := NewMemory(map[string]float64{"USD": 1.1})
+
+	tr, err := NewRates(hclog.NewNullLogger(), mp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mp.Fail(errors.New("unavailable"))
+
+	// the retry would be after the next refresh, which is at most a few
+	// days away over a weekend or holiday, so the refresh gives up
+	tr.refresh(context.Background(), Schedule{Interval: time.Millisecond, Retry: 7 * 24 * time.Hour})
+
+	// retries are disabled
+	tr.refresh(context.Background(), Schedule{})
+}
+
+func TestRefreshRatesRetriesStaleSnapshot(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "snapshot.json")
+	mp := NewMemory(SampleRates)
+
+	_, err := NewRates(hclog.NewNullLogger(), mp, WithSnapshot(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the service starts from the snapshot while the provider is down
+	mp.Fail(errors.New("unavailable"))
+
+	tr, err := NewRates(hclog.NewNullLogger(), mp, WithSnapshot(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the first scheduled refresh is at least a day away
+	tr.RefreshRates(ctx, Schedule{Interval: 24 * time.Hour, Retry: 5 * time.Millisecond})
+
+	// the provider recovers shortly after the start
+	time.Sleep(20 * time.Millisecond)
+	mp.Fail(nil)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for tr.Stale() {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the stale rates to be refreshed before the first scheduled refresh")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// publishedMemory is a Memory provider which reports a publication date
+type publishedMemory struct {
+	*Memory
+
+	mu        sync.Mutex
+	published time.Time
+}
+
+func (p *publishedMemory) Published() (time.Time, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.published, true
+}
+
+func (p *publishedMemory) publish(t time.Time, rates map[string]float64) {
+	p.mu.Lock()
+	p.published = t
+	p.mu.Unlock()
+
+	p.Set(rates)
+}
+
+func TestRefreshRetriesOutdatedPublication(t *testing.T) {
+	s := Schedule{Interval: 24 * time.Hour, Retry: 5 * time.Millisecond}
+	due := s.LastPublication(time.Now())
+
+	mp := &publishedMemory{Memory: NewMemory(map[string]float64{"USD": 1.1}), published: due.AddDate(0, 0, -1)}
+	tr, err := NewRates(hclog.NewNullLogger(), mp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		tr.refresh(context.Background(), s)
+		close(done)
+	}()
+
+	// the provider keeps returning the previous publication
+	time.Sleep(20 * time.Millisecond)
+
+	select {
+	case <-done:
+		t.Fatal("expected the outdated publication to be retried")
+	default:
+	}
+
+	mp.publish(due, map[string]float64{"USD": 1.2})
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the refresh to finish once the publication is due")
+	}
+
+	if r, _ := tr.GetRate("EUR", "USD"); r != 1.2 {
+		t.Fatalf("expected the latest publication 1.2, got %f", r)
+	}
+}
@@ -0,0 +1,67 @@
+package data
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Simulation configures the simulated fluctuations applied to the rates
+type Simulation struct {
+	// Seed for the random walk, simulations with the same seed and
+	// starting rates produce the same sequence of rates
+	Seed int64
+	// Interval is the time between changes to the rates
+	Interval time.Duration
+	// MaxDrift is the maximum change to a rate in a single step as a
+	// fraction of the rate, e.g. 0.01 allows a change of +/- 1%
+	MaxDrift float64
+	// MeanReversion is the fraction of the distance between the current rate
+	// and the provider rate which is removed every step, 0 disables reversion
+	MeanReversion float64
+}
+
+// Simulator applies a seeded random walk to the rates
+type Simulator struct {
+	cfg Simulation
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// NewSimulator creates a new simulator with the given configuration
+func NewSimulator(cfg Simulation) *Simulator {
+	return &Simulator{cfg: cfg, rand: rand.New(rand.NewSource(cfg.Seed))}
+}
+
+// step applies a single change to every rate, the rates are modified in place
+// and revert towards the anchor rates, usually the rates from the provider
+func (s *Simulator) step(rates, anchor map[string]float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// iterate in a fixed order so the same seed always produces the same rates
+	keys := make([]string, 0, len(rates))
+	for k := range rates {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		// all rates are quoted against EUR
+		if k == "EUR" {
+			continue
+		}
+
+		v := rates[k]
+		if a, ok := anchor[k]; ok {
+			v += (a - v) * s.cfg.MeanReversion
+		}
+
+		// change is evenly distributed between -MaxDrift and +MaxDrift
+		change := (s.rand.Float64()*2 - 1) * s.cfg.MaxDrift
+
+		rates[k] = v * (1 + change)
+	}
+}
@@ -0,0 +1,82 @@
+package data
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+func simulate(t *testing.T, cfg Simulation, steps int) []float64 {
+	tr, err := NewRates(hclog.NewNullLogger(), NewMemory(SampleRates))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewSimulator(cfg)
+	rates := []float64{}
+	for i := 0; i < steps; i++ {
+		tr.Tick(s)
+
+		r, err := tr.GetRate("EUR", "USD")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rates = append(rates, r)
+	}
+
+	return rates
+}
+
+func TestSimulationIsReproducible(t *testing.T) {
+	cfg := Simulation{Seed: 42, MaxDrift: 0.01, MeanReversion: 0.1}
+
+	a := simulate(t, cfg, 100)
+	b := simulate(t, cfg, 100)
+
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("expected step %d to produce the same rate, got %f and %f", i, a[i], b[i])
+		}
+	}
+
+	cfg.Seed = 43
+	c := simulate(t, cfg, 100)
+	if a[99] == c[99] {
+		t.Fatal("expected a different seed to produce different rates")
+	}
+}
+
+func TestSimulationMovesInBothDirections(t *testing.T) {
+	rates := simulate(t, Simulation{Seed: 1, MaxDrift: 0.01}, 100)
+
+	up, down := false, false
+	prev := SampleRates["USD"]
+	for _, r := range rates {
+		up = up || r > prev
+		down = down || r < prev
+
+		// a single step can not move the rate more than the max drift
+		if math.Abs(r/prev-1) > 0.01+1e-9 {
+			t.Fatalf("rate %f drifted further than allowed from %f", r, prev)
+		}
+
+		prev = r
+	}
+
+	if !up || !down {
+		t.Fatalf("expected rates to move up and down, up: %t down: %t", up, down)
+	}
+}
+
+func TestSimulationRevertsToProviderRate(t *testing.T) {
+	// full reversion without drift always returns to the provider rate
+	rates := simulate(t, Simulation{Seed: 1, MaxDrift: 0, MeanReversion: 1}, 10)
+
+	for _, r := range rates {
+		if r != SampleRates["USD"] {
+			t.Fatalf("expected rate %f, got %f", SampleRates["USD"], r)
+		}
+	}
+}
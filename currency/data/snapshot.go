@@ -0,0 +1,74 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Snapshot is a set of rates along with the time they were fetched from the provider
+type Snapshot struct {
+	Rates   map[string]float64 `json:"rates"`
+	Fetched time.Time          `json:"fetched"`
+}
+
+// saveSnapshot writes the snapshot to the given path
+func saveSnapshot(path string, s *Snapshot) error {
+	d, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("unable to encode snapshot: %w", err)
+	}
+
+	err = writeFileAtomic(path, d)
+	if err != nil {
+		return fmt.Errorf("unable to write snapshot file: %w", err)
+	}
+
+	return nil
+}
+
+// writeFileAtomic writes the data to the file at path, the file is written
+// to a temporary location first so a crash never leaves a partial file
+func writeFileAtomic(path string, d []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.Write(d)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// loadSnapshot reads a snapshot previously written with saveSnapshot
+func loadSnapshot(path string) (*Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open snapshot file: %w", err)
+	}
+	defer f.Close()
+
+	s := &Snapshot{}
+	err = json.NewDecoder(f).Decode(s)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode snapshot: %w", err)
+	}
+
+	if len(s.Rates) == 0 {
+		return nil, fmt.Errorf("snapshot %s contains no rates", path)
+	}
+
+	return s, nil
+}
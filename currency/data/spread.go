@@ -0,0 +1,130 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+)
+
+// Spread is the margin applied on each side of the mid rate, the bid is
+// mid * (1 - spread) and the ask is mid * (1 + spread). Percent and
+// BasisPoints are added together so either or both can be used
+type Spread struct {
+	// Percent is the margin as a percentage of the mid rate, e.g. 0.5 is 0.5%
+	Percent float64 `json:"percent"`
+	// BasisPoints is the margin in hundredths of a percent, e.g. 50 is 0.5%
+	BasisPoints float64 `json:"basis_points"`
+}
+
+// fraction returns the spread as an exact fraction of the mid rate
+func (s Spread) fraction() *big.Rat {
+	p := new(big.Rat).Quo(decimal(s.Percent), big.NewRat(100, 1))
+	bp := new(big.Rat).Quo(decimal(s.BasisPoints), big.NewRat(10000, 1))
+
+	return p.Add(p, bp)
+}
+
+// valid returns true when the spread is at least 0 and less than 100%
+func (s Spread) valid() bool {
+	f := s.fraction()
+
+	return f.Sign() >= 0 && f.Cmp(big.NewRat(1, 1)) < 0
+}
+
+// Side is the side of a conversion taken by the client, it selects whether
+// the bid or the ask of a spread profile is used
+type Side int
+
+const (
+	// Sell converts an amount the client sells, the base currency is bought
+	// from the client at the bid so the margin is subtracted
+	Sell Side = iota
+	// Buy converts the price of an amount the client buys, e.g. at checkout, the
+	// base currency is sold to the client at the ask so the margin is added
+	Buy
+)
+
+// Profile is a named set of spreads, e.g. for a sales channel
+type Profile struct {
+	// Default is the spread for pairs which are not listed in Pairs
+	Default Spread `json:"default"`
+	// Pairs are the spreads for currency pairs written as BASE/DEST, e.g. EUR/USD.
+	// The spread for a pair also applies to the inverse pair
+	Pairs map[string]Spread `json:"pairs"`
+}
+
+// spread returns the spread for the currency pair
+func (p Profile) spread(base, dest string) Spread {
+	if s, ok := p.Pairs[base+"/"+dest]; ok {
+		return s
+	}
+
+	if s, ok := p.Pairs[dest+"/"+base]; ok {
+		return s
+	}
+
+	return p.Default
+}
+
+// WithProfiles sets the spread profiles which can be requested by name
+func WithProfiles(profiles map[string]Profile) Option {
+	return func(e *ExchangeRates) {
+		e.profiles = profiles
+	}
+}
+
+// LoadProfiles reads spread profiles from a JSON file containing an object of
+// profile names to profiles, e.g. {"retail": {"default": {"percent": 1.5},
+// "pairs": {"EUR/USD": {"basis_points": 50}}}}
+func LoadProfiles(path string) (map[string]Profile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open profile file: %w", err)
+	}
+	defer f.Close()
+
+	profiles := map[string]Profile{}
+	err = json.NewDecoder(f).Decode(&profiles)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode profiles: %w", err)
+	}
+
+	for name, p := range profiles {
+		if !p.Default.valid() {
+			return nil, fmt.Errorf("invalid default spread in profile %s, the spread must be at least 0 and less than 100%%", name)
+		}
+
+		// normalize the pairs so lookups match the upper case currency codes
+		spreads := map[string]Spread{}
+		for pair, s := range p.Pairs {
+			codes := strings.Split(strings.ToUpper(pair), "/")
+			if len(codes) != 2 || codes[0] == "" || codes[1] == "" {
+				return nil, fmt.Errorf("invalid currency pair %q in profile %s, expected BASE/DEST", pair, name)
+			}
+
+			if !s.valid() {
+				return nil, fmt.Errorf("invalid spread for %s in profile %s, the spread must be at least 0 and less than 100%%", pair, name)
+			}
+
+			spreads[codes[0]+"/"+codes[1]] = s
+		}
+
+		profiles[name] = Profile{Default: p.Default, Pairs: spreads}
+	}
+
+	return profiles, nil
+}
+
+// HasProfile returns true when the named profile exists, the empty name
+// refers to the mid rate without a spread and always exists
+func (e *ExchangeRates) HasProfile(name string) bool {
+	if name == "" {
+		return true
+	}
+
+	_, ok := e.profiles[name]
+
+	return ok
+}
@@ -0,0 +1,137 @@
+package data
+
+import (
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+const testProfiles = `{
+	"retail": {
+		"default": {"percent": 1.5},
+		"pairs": {"eur/usd": {"basis_points": 50}}
+	},
+	"wholesale": {
+		"default": {"percent": 0.1, "basis_points": 5}
+	}
+}`
+
+func writeProfiles(t *testing.T, profiles string) string {
+	p := filepath.Join(t.TempDir(), "profiles.json")
+
+	err := os.WriteFile(p, []byte(profiles), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return p
+}
+
+func TestGetQuoteWithProfile(t *testing.T) {
+	profiles, err := LoadProfiles(writeProfiles(t, testProfiles))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tr, err := NewRates(hclog.NewNullLogger(), NewMemory(map[string]float64{"USD": 1.1, "GBP": 0.8}), WithProfiles(profiles))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		base    string
+		dest    string
+		profile string
+		spread  float64
+	}{
+		{"mid", "EUR", "USD", "", 0},
+		{"pair", "EUR", "USD", "retail", 0.005},
+		{"inverse pair", "USD", "EUR", "retail", 0.005},
+		{"default", "EUR", "GBP", "retail", 0.015},
+		{"percent and basis points", "EUR", "GBP", "wholesale", 0.0015},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := tr.GetQuote(tc.base, tc.dest, tc.profile)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			mid, _ := tr.GetRate(tc.base, tc.dest)
+			if q.Rate != mid {
+				t.Fatalf("expected mid rate %f, got %f", mid, q.Rate)
+			}
+
+			if math.Abs(q.Bid-mid*(1-tc.spread)) > 1e-12 || math.Abs(q.Ask-mid*(1+tc.spread)) > 1e-12 {
+				t.Fatalf("expected spread of %f, got bid %f ask %f for mid %f", tc.spread, q.Bid, q.Ask, mid)
+			}
+		})
+	}
+
+	_, err = tr.GetQuote("EUR", "USD", "unknown")
+	if err == nil {
+		t.Fatal("expected error for unknown profile")
+	}
+}
+
+func TestConvertUsesSide(t *testing.T) {
+	profiles, err := LoadProfiles(writeProfiles(t, testProfiles))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tr, err := NewRates(hclog.NewNullLogger(), NewMemory(map[string]float64{"USD": 1.1}), WithProfiles(profiles))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		side   Side
+		amount *big.Rat
+		rate   float64
+	}{
+		{"sell at the bid", Sell, big.NewRat(10945, 100), 1.0945}, // 100 * 1.1 * (1 - 0.005)
+		{"buy at the ask", Buy, big.NewRat(11055, 100), 1.1055},   // 100 * 1.1 * (1 + 0.005)
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r, rate, err := tr.Convert("EUR", "USD", "retail", tc.side, big.NewRat(100, 1))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if r.Cmp(tc.amount) != 0 {
+				t.Fatalf("expected %s, got %s", tc.amount.FloatString(2), r.FloatString(2))
+			}
+
+			if math.Abs(rate-tc.rate) > 1e-12 {
+				t.Fatalf("expected rate %f, got %f", tc.rate, rate)
+			}
+		})
+	}
+}
+
+func TestLoadProfilesRejectsInvalidProfiles(t *testing.T) {
+	tests := map[string]string{
+		"pair":   `{"retail": {"pairs": {"EURUSD": {"percent": 1}}}}`,
+		"spread": `{"retail": {"default": {"percent": 100}}}`,
+		"json":   `{"retail": `,
+	}
+
+	for name, profiles := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadProfiles(writeProfiles(t, profiles))
+			if err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
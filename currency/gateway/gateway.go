@@ -0,0 +1,114 @@
+// Package gateway serves the Currency gRPC service as HTTP and JSON for
+// clients which can not use gRPC, such as browsers and spreadsheets
+package gateway
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/hashicorp/go-hclog"
+	protos "github.com/hnsia/go-nic/currency/protos/currency/currency"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// marshaler writes messages using the JSON mapping for protocol buffers, the
+// same mapping is used for the details of errors
+var marshaler = protojson.MarshalOptions{EmitUnpopulated: true}
+
+// Gateway translates HTTP requests to calls on a Currency client
+type Gateway struct {
+	client protos.CurrencyClient
+	log    hclog.Logger
+}
+
+// New creates a gateway which calls the currency service using the client
+func New(cc protos.CurrencyClient, l hclog.Logger) *Gateway {
+	return &Gateway{client: cc, log: l}
+}
+
+// Handler returns the routes for the gateway:
+//
+//	GET /rates/{base}/{dest}?profile=  returns the rate, see GetRate
+//	GET /rates/stream?pair=EUR/USD&profile=  streams rate updates as Server-Sent Events, see SubscribeRates
+func (g *Gateway) Handler() http.Handler {
+	sm := mux.NewRouter()
+
+	getRouter := sm.Methods(http.MethodGet).Subrouter()
+	getRouter.HandleFunc("/rates/stream", g.StreamRates)
+	getRouter.HandleFunc("/rates/{base:[A-Za-z]{3}}/{dest:[A-Za-z]{3}}", g.GetRate)
+
+	return sm
+}
+
+// GetRate returns the rate between the currencies in the path
+func (g *Gateway) GetRate(rw http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	g.log.Debug("Handle GetRate", "base", vars["base"], "destination", vars["dest"])
+
+	resp, err := g.client.GetRate(r.Context(), &protos.RateRequest{
+		BaseCode:        strings.ToUpper(vars["base"]),
+		DestinationCode: strings.ToUpper(vars["dest"]),
+		Profile:         r.URL.Query().Get("profile"),
+	})
+	if err != nil {
+		g.writeError(rw, status.Convert(err))
+		return
+	}
+
+	g.writeJSON(rw, http.StatusOK, resp)
+}
+
+// writeJSON writes the message as the response body
+func (g *Gateway) writeJSON(rw http.ResponseWriter, code int, m proto.Message) {
+	b, err := marshaler.Marshal(m)
+	if err != nil {
+		g.log.Error("Unable to marshal response", "error", err)
+		http.Error(rw, "Unable to marshal response", http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(code)
+	fmt.Fprintf(rw, "%s\n", b)
+}
+
+// writeError writes the status as a JSON google.rpc.Status, the details such
+// as the request attached to an InvalidArgument error are included with their type
+func (g *Gateway) writeError(rw http.ResponseWriter, s *status.Status) {
+	g.writeJSON(rw, httpStatus(s.Code()), s.Proto())
+}
+
+// httpStatus returns the HTTP status code for the gRPC code
+func httpStatus(c codes.Code) int {
+	switch c {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499 // client closed request
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
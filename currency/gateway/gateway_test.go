@@ -0,0 +1,203 @@
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hnsia/go-nic/currency/data"
+	protos "github.com/hnsia/go-nic/currency/protos/currency/currency"
+	"github.com/hnsia/go-nic/currency/server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// newTestGateway starts the currency service and a gateway in front of it
+func newTestGateway(t *testing.T) (*httptest.Server, *data.ExchangeRates) {
+	r, err := data.NewRates(hclog.NewNullLogger(), data.NewMemory(data.SampleRates))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gs := grpc.NewServer()
+	protos.RegisterCurrencyServer(gs, server.NewCurrency(r, server.Queue{}, nil, hclog.NewNullLogger()))
+	go gs.Serve(l)
+	t.Cleanup(gs.Stop)
+
+	conn, err := grpc.NewClient(l.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	hs := httptest.NewServer(New(protos.NewCurrencyClient(conn), hclog.NewNullLogger()).Handler())
+	t.Cleanup(hs.Close)
+
+	return hs, r
+}
+
+func TestGetRate(t *testing.T) {
+	hs, _ := newTestGateway(t)
+
+	resp, err := http.Get(hs.URL + "/rates/eur/USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	rr := struct {
+		Rate            float64
+		DestinationCode string
+		AsOf            time.Time
+	}{}
+
+	err = json.NewDecoder(resp.Body).Decode(&rr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rr.Rate != data.SampleRates["USD"] || rr.DestinationCode != "USD" || rr.AsOf.IsZero() {
+		t.Fatalf("unexpected rate %+v", rr)
+	}
+}
+
+func TestGetRateErrorDetails(t *testing.T) {
+	hs, _ := newTestGateway(t)
+
+	resp, err := http.Get(hs.URL + "/rates/EUR/EUR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", resp.StatusCode)
+	}
+
+	// the error is a google.rpc.Status with the request as a detail
+	s := struct {
+		Code    int
+		Message string
+		Details []map[string]any
+	}{}
+
+	err = json.NewDecoder(resp.Body).Decode(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Code != 3 || s.Message == "" || len(s.Details) != 1 {
+		t.Fatalf("expected invalid argument with details, got %+v", s)
+	}
+
+	if s.Details[0]["@type"] != "type.googleapis.com/RateRequest" || s.Details[0]["BaseCode"] != "EUR" {
+		t.Fatalf("expected the rate request as a detail, got %v", s.Details[0])
+	}
+}
+
+// event is a single Server-Sent Event
+type event struct {
+	name string
+	data string
+}
+
+// readEvents sends the events read from the body to the channel
+func readEvents(body *bufio.Scanner, events chan<- event) {
+	defer close(events)
+
+	e := event{}
+	for body.Scan() {
+		line := body.Text()
+
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			e.name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			e.data = strings.TrimPrefix(line, "data: ")
+		case line == "":
+			events <- e
+			e = event{}
+		}
+	}
+}
+
+func TestStreamRates(t *testing.T) {
+	hs, r := newTestGateway(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, hs.URL+"/rates/stream?pair=EUR/USD&pair=GBP/GBP", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected an event stream, got %s", ct)
+	}
+
+	events := make(chan event)
+	go readEvents(bufio.NewScanner(resp.Body), events)
+
+	// the invalid pair is reported without ending the stream
+	e := <-events
+	if e.name != "error" || !strings.Contains(e.data, "type.googleapis.com/RateRequest") {
+		t.Fatalf("expected an error event with details, got %v", e)
+	}
+
+	// changes are broadcast until the subscription has been received
+	sim := data.NewSimulator(data.Simulation{Seed: 1, MaxDrift: 0.01})
+	tick := time.NewTicker(10 * time.Millisecond)
+	defer tick.Stop()
+
+	timeout := time.After(5 * time.Second)
+
+	for {
+		select {
+		case e := <-events:
+			if e.name != "rate" || !strings.Contains(e.data, `"DestinationCode":"USD"`) {
+				t.Fatalf("expected a rate event for USD, got %v", e)
+			}
+
+			return
+		case <-tick.C:
+			r.Tick(sim)
+		case <-timeout:
+			t.Fatal("timed out waiting for a rate event")
+		}
+	}
+}
+
+func TestStreamRatesRequiresPairs(t *testing.T) {
+	hs, _ := newTestGateway(t)
+
+	for _, q := range []string{"", "?pair=EURUSD"} {
+		resp, err := http.Get(hs.URL + "/rates/stream" + q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected status 400 for %q, got %d", q, resp.StatusCode)
+		}
+	}
+}
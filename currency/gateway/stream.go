@@ -0,0 +1,109 @@
+package gateway
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	protos "github.com/hnsia/go-nic/currency/protos/currency/currency"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// StreamRates subscribes to the currency pairs in the pair query parameters,
+// e.g. pair=EUR/USD, and relays the updates as Server-Sent Events. Rate
+// updates are sent as rate events containing a RateResponse, errors for a
+// subscription are sent as error events containing a google.rpc.Status. The
+// stream ends with an error event when the currency service ends the stream
+func (g *Gateway) StreamRates(rw http.ResponseWriter, r *http.Request) {
+	profile := r.URL.Query().Get("profile")
+
+	requests := []*protos.RateRequest{}
+	for _, p := range r.URL.Query()["pair"] {
+		pair := strings.Split(strings.ToUpper(p), "/")
+		if len(pair) != 2 || len(pair[0]) != 3 || len(pair[1]) != 3 {
+			g.writeError(rw, invalidArgument("Invalid currency pair %q, expected BASE/DEST", p))
+			return
+		}
+
+		requests = append(requests, &protos.RateRequest{BaseCode: pair[0], DestinationCode: pair[1], Profile: profile})
+	}
+
+	if len(requests) == 0 {
+		g.writeError(rw, invalidArgument("At least one pair query parameter is required, e.g. pair=EUR/USD"))
+		return
+	}
+
+	f, ok := rw.(http.Flusher)
+	if !ok {
+		g.writeError(rw, status.New(codes.Internal, "Streaming is not supported by the connection"))
+		return
+	}
+
+	// the stream is cancelled when the client disconnects
+	src, err := g.client.SubscribeRates(r.Context())
+	if err != nil {
+		g.writeError(rw, status.Convert(err))
+		return
+	}
+
+	for _, rr := range requests {
+		err := src.Send(rr)
+		if err != nil {
+			g.writeError(rw, status.Convert(err))
+			return
+		}
+	}
+
+	g.log.Debug("Streaming rates", "pairs", len(requests), "profile", profile)
+
+	rw.Header().Set("Content-Type", "text/event-stream")
+	rw.Header().Set("Cache-Control", "no-cache")
+	rw.WriteHeader(http.StatusOK)
+	f.Flush()
+
+	for {
+		m, err := src.Recv()
+		if err != nil {
+			// the client has gone away, there is no one to tell
+			if r.Context().Err() != nil {
+				return
+			}
+
+			if err == io.EOF {
+				err = status.Error(codes.Unavailable, "Currency service ended the stream")
+			}
+
+			g.writeEvent(rw, "error", status.Convert(err).Proto())
+			f.Flush()
+
+			return
+		}
+
+		if m.GetError() != nil {
+			g.writeEvent(rw, "error", m.GetError())
+		} else {
+			g.writeEvent(rw, "rate", m.GetRateResponse())
+		}
+
+		f.Flush()
+	}
+}
+
+// writeEvent writes the message as a Server-Sent Event, the JSON is written
+// on a single line so it fits in a single data field
+func (g *Gateway) writeEvent(rw http.ResponseWriter, event string, m proto.Message) {
+	b, err := marshaler.Marshal(m)
+	if err != nil {
+		g.log.Error("Unable to marshal event", "error", err)
+		return
+	}
+
+	fmt.Fprintf(rw, "event: %s\ndata: %s\n\n", event, b)
+}
+
+func invalidArgument(format string, a ...any) *status.Status {
+	return status.Newf(codes.InvalidArgument, format, a...)
+}
@@ -1,45 +1,387 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
+	"github.com/hnsia/go-nic/currency/certs"
 	"github.com/hnsia/go-nic/currency/data"
+	"github.com/hnsia/go-nic/currency/metrics"
 	protos "github.com/hnsia/go-nic/currency/protos/currency/currency"
 	"github.com/hnsia/go-nic/currency/server"
+	"github.com/nicholasjackson/env"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 )
 
+var bindAddress = env.String("BIND_ADDRESS", false, ":9092", "Bind address for the gRPC server")
+var logLevel = env.String("LOG_LEVEL", false, "info", "Log output level for the server [trace, debug, info, warn, error]")
+var reflectionEnabled = env.Bool("REFLECTION", false, false, "Register the gRPC reflection service, allows clients such as grpcurl to discover the service methods")
+var rateProvider = env.String("RATE_PROVIDER", false, "ecb", "Comma separated sources of exchange rates in the order they are tried, e.g. ecb,file [ecb, file, memory]")
+var ecbURL = env.String("ECB_URL", false, data.ECBDailyURL, "Location of the ECB daily rates feed, used by the ecb provider")
+var rateFile = env.String("RATE_FILE", false, "", "Path to a JSON or CSV rate file, used by the file provider")
+var providerTimeout = env.Duration("PROVIDER_TIMEOUT", false, 10*time.Second, "Time allowed for a single attempt to fetch rates from a provider")
+var providerRetries = env.Int("PROVIDER_RETRIES", false, 2, "Number of times a failed fetch is retried before the next provider is tried")
+var providerBackoff = env.Duration("PROVIDER_BACKOFF", false, 500*time.Millisecond, "Wait before the first retry of a failed fetch, doubled for every retry")
+var providerFailureThreshold = env.Int("PROVIDER_FAILURE_THRESHOLD", false, 3, "Number of consecutive failed fetches after which a provider is skipped for the cool down, 0 disables the circuit breaker")
+var providerCoolDown = env.Duration("PROVIDER_COOL_DOWN", false, 5*time.Minute, "Time a failing provider is skipped before it is tried again")
+var snapshotPath = env.String("RATE_SNAPSHOT", false, "./rates_snapshot.json", "Path to persist the last fetched rates, used at startup when the provider is unavailable, empty disables snapshots")
+var historyURL = env.String("RATE_HISTORY_URL", false, "", "Location of an ECB historical rates feed, e.g. the 90 day or full history XML, or a file:// url to a local copy")
+var spreadProfiles = env.String("SPREAD_PROFILES", false, "", "Path to a JSON file of named spread profiles which clients can request bid and ask rates for, empty disables profiles")
+var refresh = env.Bool("REFRESH", false, true, "Refresh the rates from the provider on a schedule, the rates are only fetched at startup when disabled")
+var refreshInterval = env.Duration("REFRESH_INTERVAL", false, 0, "Refresh the rates at a fixed interval, when 0 the rates are refreshed daily at REFRESH_TIME")
+var refreshTime = env.String("REFRESH_TIME", false, "16:15", "Time of day the rates are refreshed as HH:MM in REFRESH_TIMEZONE, shortly after the ECB publishes the rates")
+var refreshRetry = env.Duration("REFRESH_RETRY_INTERVAL", false, 15*time.Minute, "Wait before a failed or outdated refresh is tried again, stale rates at startup are retried too, retries stop at the next scheduled refresh, 0 disables retries")
+var refreshTimezone = env.String("REFRESH_TIMEZONE", false, data.ECBTimeZone, "Time zone for REFRESH_TIME, weekends and holidays")
+var candleResolutions = env.String("CANDLE_RESOLUTIONS", false, "1m,1h,1d", "Comma separated resolutions candles are recorded for [1m, 1h, 1d], empty disables candles")
+var candlePairs = env.String("CANDLE_PAIRS", false, "", "Comma separated currency pairs candles are recorded for, e.g. EUR/USD,GBP/JPY, empty records every currency against EUR")
+var candleSize = env.Int("CANDLE_SIZE", false, 1000, "Number of candles kept for each pair and resolution")
+var candleFile = env.String("CANDLE_FILE", false, "", "Path to persist the candles, they are loaded at startup and saved every minute and at shutdown, empty disables persistence")
+var recordFile = env.String("RECORD_FILE", false, "", "Path to append every change to the rates to as newline delimited JSON, the recording can be replayed with REPLAY_FILE, empty disables recording")
+var replayFile = env.String("REPLAY_FILE", false, "", "Path to a recording made with RECORD_FILE which is replayed instead of fetching rates from RATE_PROVIDER, refreshes and snapshots are disabled while replaying, the replay starts when the first client subscribes to a rate")
+var replaySpeed = env.Float64("REPLAY_SPEED", false, 1, "Speed the recording is replayed at as a multiple of the original speed, e.g. 10 replays ten times faster")
+var simulate = env.Bool("SIMULATION", false, false, "Simulate fluctuations in the rates, when disabled the provider rates are served unchanged")
+var simulationSeed = env.Int("SIMULATION_SEED", false, 0, "Seed for the simulated fluctuations, 0 uses a random seed")
+var simulationInterval = env.Duration("SIMULATION_INTERVAL", false, 5*time.Second, "Time between simulated fluctuations")
+var simulationMaxDrift = env.Float64("SIMULATION_MAX_DRIFT", false, 0.01, "Maximum change to a rate in a single simulation step as a fraction of the rate")
+var simulationMeanReversion = env.Float64("SIMULATION_MEAN_REVERSION", false, 0.1, "Fraction of the distance to the provider rate removed every simulation step")
+var healthMaxRateAge = env.Duration("HEALTH_MAX_RATE_AGE", false, 144*time.Hour, "Report the service as not serving when the rates were fetched from the provider longer ago than this, the default covers the longest TARGET closure over Easter plus a day of failed refreshes, 0 disables the check")
+var healthInterval = env.Duration("HEALTH_CHECK_INTERVAL", false, 30*time.Second, "Interval between updates of the health status")
+var tlsCert = env.String("TLS_CERT_FILE", false, "", "Path to the PEM encoded server certificate, enables TLS when set")
+var tlsKey = env.String("TLS_KEY_FILE", false, "", "Path to the PEM encoded private key for the server certificate")
+var tlsClientCA = env.String("TLS_CLIENT_CA_FILE", false, "", "Path to a PEM encoded CA bundle, when set clients must present a certificate signed by one of the CAs")
+var subscriberQueueSize = env.Int("SUBSCRIBER_QUEUE_SIZE", false, server.DefaultQueueSize, "Number of messages which can wait to be sent to each SubscribeRates stream")
+var subscriberOverflow = env.String("SUBSCRIBER_OVERFLOW_POLICY", false, "drop_oldest", "Action when a SubscribeRates stream does not keep up and its queue is full [drop_oldest, coalesce, disconnect]")
+var shutdownTimeout = env.Duration("SHUTDOWN_TIMEOUT", false, 30*time.Second, "Time allowed for in flight requests to complete at shutdown before connections are closed")
+var metricsAddress = env.String("METRICS_BIND_ADDRESS", false, ":9093", "Bind address for the HTTP server exposing Prometheus metrics at /metrics, empty disables metrics")
+
 func main() {
-	logger := hclog.Default()
+	err := env.Parse()
+	if err == nil {
+		err = validateConfig()
+	}
+
+	if err != nil {
+		hclog.Default().Error("Invalid configuration", "error", err)
+		os.Exit(1)
+	}
+
+	logger := hclog.New(&hclog.LoggerOptions{
+		Name:  "currency",
+		Level: hclog.LevelFromString(*logLevel),
+	})
+
+	// the context is cancelled when the service is asked to stop, this stops the background tasks
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	// metrics are registered with a dedicated registry, it is served by the side listener
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	m := metrics.New(reg)
+
+	// a replay takes the place of the provider, the replayed rates are not
+	// written to the snapshot so it still holds the last fetched rates
+	var rp data.RateProvider
+	var replay *data.Replay
+	if *replayFile != "" {
+		replay, err = data.NewReplay(*replayFile, *replaySpeed)
+		rp = replay
+	} else {
+		rp, err = newChain(*rateProvider, logger, m)
+	}
 
-	rates, err := data.NewRates(logger)
+	if err != nil {
+		logger.Error("Unable to create rate provider", "error", err)
+		os.Exit(1)
+	}
+
+	opts := []data.Option{data.WithMetrics(m)}
+	if *snapshotPath != "" && replay == nil {
+		opts = append(opts, data.WithSnapshot(*snapshotPath))
+	}
+
+	var recorder *data.Recorder
+	if *recordFile != "" {
+		recorder, err = data.NewRecorder(*recordFile)
+		if err != nil {
+			logger.Error("Unable to open recording", "error", err)
+			os.Exit(1)
+		}
+
+		logger.Info("Recording rates", "path", *recordFile)
+		opts = append(opts, data.WithRecorder(recorder))
+	}
+
+	// aggregate the changes to the rates into candles, the values have been validated
+	var candles *data.Candles
+	if *candleResolutions != "" {
+		cfg := data.CandleConfig{Pairs: splitList(*candlePairs), Size: *candleSize, Path: *candleFile}
+		for _, r := range splitList(*candleResolutions) {
+			res, _ := data.ParseResolution(r)
+			cfg.Resolutions = append(cfg.Resolutions, res)
+		}
+
+		candles, err = data.NewCandles(cfg)
+		if err != nil {
+			logger.Error("Unable to load candles", "error", err)
+			os.Exit(1)
+		}
+
+		if *candleFile != "" {
+			candles.SaveEvery(ctx, time.Minute, logger)
+		}
+
+		opts = append(opts, data.WithCandles(candles))
+	}
+
+	if *spreadProfiles != "" {
+		profiles, err := data.LoadProfiles(*spreadProfiles)
+		if err != nil {
+			logger.Error("Unable to load spread profiles", "error", err)
+			os.Exit(1)
+		}
+
+		logger.Info("Loaded spread profiles", "profiles", len(profiles))
+		opts = append(opts, data.WithProfiles(profiles))
+	}
+
+	rates, err := data.NewRates(logger, rp, opts...)
 	if err != nil {
 		logger.Error("Unable to generate rates", "error", err)
 		os.Exit(1)
 	}
 
-	// create a new gRPC server, use WithInsecure to allow http connections
-	gs := grpc.NewServer()
+	if rates.Stale() {
+		logger.Warn("Provider unavailable, serving stale rates", "fetched", rates.Fetched())
+	}
+
+	// load the historical rates when a feed has been configured
+	if *historyURL != "" {
+		err = rates.LoadHistory(data.NewECB(*historyURL))
+		if err != nil {
+			logger.Error("Unable to load historical rates", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// refreshes are skipped on weekends and TARGET holidays when no rates are published,
+	// the values have been validated
+	if *refresh && replay == nil {
+		publish, _ := parseTimeOfDay(*refreshTime)
+		loc, _ := time.LoadLocation(*refreshTimezone)
+
+		rates.RefreshRates(ctx, data.Schedule{
+			Interval:    *refreshInterval,
+			PublishTime: publish,
+			Location:    loc,
+			Retry:       *refreshRetry,
+		})
+	}
+
+	if *simulate {
+		seed := int64(*simulationSeed)
+		if seed == 0 {
+			seed = time.Now().UnixNano()
+		}
+
+		// log the seed so the simulation can be reproduced
+		logger.Info("Simulating rate fluctuations", "seed", seed, "interval", *simulationInterval)
+
+		rates.MonitorRates(ctx, data.NewSimulator(data.Simulation{
+			Seed:          seed,
+			Interval:      *simulationInterval,
+			MaxDrift:      *simulationMaxDrift,
+			MeanReversion: *simulationMeanReversion,
+		}))
+	}
+
+	// create a new gRPC server with interceptors which log, recover from panics and count requests
+	ic := server.NewInterceptors(logger)
+	gopts := ic.Options()
 
-	// create an instance of the currency server
-	cs := server.NewCurrency(rates, logger)
+	// serve TLS when a certificate has been configured, the files are reloaded when they change
+	if *tlsCert != "" {
+		tf, err := certs.Load(*tlsCert, *tlsKey, *tlsClientCA, logger)
+		if err != nil {
+			logger.Error("Unable to load TLS files", "error", err)
+			os.Exit(1)
+		}
+
+		logger.Info("Serving TLS", "cert", *tlsCert, "mtls", *tlsClientCA != "")
+		gopts = append(gopts, grpc.Creds(credentials.NewTLS(tf.ServerConfig())))
+	}
+
+	gs := grpc.NewServer(gopts...)
+	reg.MustRegister(ic)
+
+	// create an instance of the currency server, the policy has been validated
+	overflow, _ := server.ParseOverflowPolicy(*subscriberOverflow)
+	cs := server.NewCurrency(rates, server.Queue{Size: *subscriberQueueSize, Overflow: overflow}, m, logger)
 
 	// register the currency server
 	protos.RegisterCurrencyServer(gs, cs)
 
+	// replay the recording through the subscriptions of the currency server,
+	// the replay starts when the first client subscribes to a rate
+	if replay != nil {
+		logger.Info("Replaying recording", "path", *replayFile, "speed", *replaySpeed)
+		cs.Replay(ctx, replay)
+	}
+
+	// register the health service which reports if the rates can be trusted
+	hs := server.NewHealth(rates, *healthMaxRateAge, logger)
+	hs.Monitor(ctx, *healthInterval)
+	healthpb.RegisterHealthServer(gs, hs)
+
 	// register the reflection service which allows clients to determine the methods for this gRPC service
-	reflection.Register(gs) // Should disable this in production
+	if *reflectionEnabled {
+		reflection.Register(gs)
+	}
+
+	var ms *http.Server
+	if *metricsAddress != "" {
+		ms = serveMetrics(*metricsAddress, reg, logger)
+	}
 
 	// create a TCP socket for inbound server connections
-	l, err := net.Listen("tcp", ":9092")
+	l, err := net.Listen("tcp", *bindAddress)
 	if err != nil {
 		logger.Error("Unable to listen", "error", err)
 		os.Exit(1)
 	}
 
+	logger.Info("Starting server", "address", *bindAddress)
+
 	// listen for requests
-	gs.Serve(l)
+	go func() {
+		err := gs.Serve(l)
+		if err != nil {
+			logger.Error("Unable to serve", "error", err)
+			os.Exit(1)
+		}
+	}()
+
+	<-ctx.Done()
+	logger.Info("Received terminate, graceful shutdown", "timeout", *shutdownTimeout)
+
+	// report not serving so load balancers stop sending new requests, then
+	// tell subscribers to reconnect elsewhere before the server stops
+	hs.Shutdown()
+	cs.Drain()
+
+	stopped := make(chan struct{})
+	go func() {
+		gs.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(*shutdownTimeout):
+		logger.Warn("Timed out waiting for requests to complete, closing connections")
+		gs.Stop()
+	}
+
+	if candles != nil {
+		err := candles.Save()
+		if err != nil {
+			logger.Error("Unable to save candles", "error", err, "path", *candleFile)
+		}
+	}
+
+	if recorder != nil {
+		err := recorder.Close()
+		if err != nil {
+			logger.Error("Unable to close recording", "error", err, "path", *recordFile)
+		}
+	}
+
+	if ms != nil {
+		tc, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+		defer cancel()
+
+		ms.Shutdown(tc)
+	}
+}
+
+// serveMetrics serves the metrics in the registry in the Prometheus text format
+func serveMetrics(addr string, reg *prometheus.Registry, l hclog.Logger) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+
+	s := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		l.Info("Serving metrics", "address", addr)
+
+		err := s.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			l.Error("Unable to serve metrics", "error", err)
+		}
+	}()
+
+	return s
+}
+
+// splitList returns the values in a comma separated list, an empty list has no values
+func splitList(s string) []string {
+	values := []string{}
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
+
+// newChain creates a provider which tries each of the comma separated providers in order
+func newChain(names string, l hclog.Logger, m *metrics.Metrics) (*data.Chain, error) {
+	links := []data.Link{}
+	for _, name := range strings.Split(names, ",") {
+		p, err := newProvider(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+
+		links = append(links, data.Link{
+			Provider:         p,
+			Timeout:          *providerTimeout,
+			Retries:          *providerRetries,
+			Backoff:          *providerBackoff,
+			FailureThreshold: *providerFailureThreshold,
+			CoolDown:         *providerCoolDown,
+		})
+	}
+
+	return data.NewChain(l, m, links...), nil
+}
+
+// newProvider returns the RateProvider for the given name
+func newProvider(name string) (data.RateProvider, error) {
+	switch name {
+	case "ecb":
+		return data.NewECB(*ecbURL), nil
+	case "file":
+		return data.NewFile(*rateFile)
+	case "memory":
+		return data.NewMemory(data.SampleRates), nil
+	}
+
+	return nil, fmt.Errorf("unknown rate provider %s", name)
 }
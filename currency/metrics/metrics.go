@@ -0,0 +1,265 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "currency"
+
+// Metrics records the Prometheus metrics for the currency service. A nil
+// *Metrics is valid and records nothing so metrics are optional
+type Metrics struct {
+	fetchDuration *prometheus.HistogramVec
+	fetchErrors   prometheus.Counter
+	attempts      *prometheus.CounterVec
+	active        *prometheus.GaugeVec
+	circuitOpen   *prometheus.GaugeVec
+	stale         prometheus.Gauge
+	fetchedTime   prometheus.Gauge
+	subscribers   prometheus.Gauge
+	subscriptions prometheus.Gauge
+	broadcasts    prometheus.Counter
+	sendFailures  prometheus.Counter
+	dropped       *prometheus.CounterVec
+	disconnects   prometheus.Counter
+
+	// mu guards fetched, the age of the rates is calculated when scraped
+	mu      sync.Mutex
+	fetched time.Time
+}
+
+// New creates the metrics and registers them with the given registerer
+func New(r prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		fetchDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "provider_fetch_duration_seconds",
+			Help:      "Time taken to fetch rates from the rate provider",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"result"}),
+		fetchErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "provider_fetch_errors_total",
+			Help:      "Number of failed attempts to fetch rates from the rate provider",
+		}),
+		attempts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "provider_attempts_total",
+			Help:      "Number of attempts to fetch rates from each provider in the chain, by result",
+		}, []string{"provider", "result"}),
+		active: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "provider_active",
+			Help:      "1 for the provider which supplied the current rates",
+		}, []string{"provider"}),
+		circuitOpen: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "provider_circuit_open",
+			Help:      "1 while a provider is skipped because it has failed repeatedly",
+		}, []string{"provider"}),
+		stale: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "rates_stale",
+			Help:      "1 when the rates were loaded from a snapshot because the provider could not be reached",
+		}),
+		fetchedTime: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "rates_fetched_timestamp_seconds",
+			Help:      "Unix time the current rates were fetched from the rate provider",
+		}),
+		subscribers: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "subscribers",
+			Help:      "Number of open SubscribeRates streams",
+		}),
+		subscriptions: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "subscriptions",
+			Help:      "Number of rates subscribed to across all streams",
+		}),
+		broadcasts: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "ticks_broadcast_total",
+			Help:      "Number of rate changes broadcast to subscribers",
+		}),
+		sendFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "send_failures_total",
+			Help:      "Number of messages which could not be sent to subscribers",
+		}),
+		dropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "subscriber_messages_dropped_total",
+			Help:      "Number of messages dropped because a subscriber queue was full, by overflow policy",
+		}, []string{"policy"}),
+		disconnects: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "subscriber_disconnects_total",
+			Help:      "Number of subscribers disconnected because they did not read messages fast enough",
+		}),
+	}
+
+	age := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "rates_age_seconds",
+		Help:      "Time since the current rates were fetched from the rate provider",
+	}, m.age)
+
+	r.MustRegister(
+		m.fetchDuration,
+		m.fetchErrors,
+		m.attempts,
+		m.active,
+		m.circuitOpen,
+		m.stale,
+		m.fetchedTime,
+		m.subscribers,
+		m.subscriptions,
+		m.broadcasts,
+		m.sendFailures,
+		m.dropped,
+		m.disconnects,
+		age,
+	)
+
+	return m
+}
+
+// ProviderFetch records the duration and result of a fetch from the rate provider
+func (m *Metrics) ProviderFetch(d time.Duration, err error) {
+	if m == nil {
+		return
+	}
+
+	result := "success"
+	if err != nil {
+		result = "error"
+		m.fetchErrors.Inc()
+	}
+
+	m.fetchDuration.WithLabelValues(result).Observe(d.Seconds())
+}
+
+// ProviderAttempt records the result of a single attempt to fetch rates from the named provider
+func (m *Metrics) ProviderAttempt(provider string, err error) {
+	if m == nil {
+		return
+	}
+
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+
+	m.attempts.WithLabelValues(provider, result).Inc()
+}
+
+// ActiveProvider records if the named provider supplied the current rates
+func (m *Metrics) ActiveProvider(provider string, active bool) {
+	if m == nil {
+		return
+	}
+
+	m.active.WithLabelValues(provider).Set(boolToFloat(active))
+}
+
+// CircuitOpen records if the named provider is skipped because it has failed repeatedly
+func (m *Metrics) CircuitOpen(provider string, open bool) {
+	if m == nil {
+		return
+	}
+
+	m.circuitOpen.WithLabelValues(provider).Set(boolToFloat(open))
+}
+
+// RatesFetched records the time the current rates were fetched and if they are stale
+func (m *Metrics) RatesFetched(fetched time.Time, stale bool) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	m.fetched = fetched
+	m.mu.Unlock()
+
+	m.fetchedTime.Set(float64(fetched.UnixNano()) / 1e9)
+
+	m.stale.Set(boolToFloat(stale))
+}
+
+// Subscribers sets the number of open SubscribeRates streams
+func (m *Metrics) Subscribers(n int) {
+	if m == nil {
+		return
+	}
+
+	m.subscribers.Set(float64(n))
+}
+
+// AddSubscriptions changes the number of subscribed rates by n
+func (m *Metrics) AddSubscriptions(n int) {
+	if m == nil {
+		return
+	}
+
+	m.subscriptions.Add(float64(n))
+}
+
+// Broadcast records a rate change sent to subscribers
+func (m *Metrics) Broadcast() {
+	if m == nil {
+		return
+	}
+
+	m.broadcasts.Inc()
+}
+
+// SendFailure records a message which could not be sent to a subscriber
+func (m *Metrics) SendFailure() {
+	if m == nil {
+		return
+	}
+
+	m.sendFailures.Inc()
+}
+
+// Dropped records n messages dropped from a full subscriber queue by the overflow policy
+func (m *Metrics) Dropped(policy string, n int) {
+	if m == nil {
+		return
+	}
+
+	m.dropped.WithLabelValues(policy).Add(float64(n))
+}
+
+// SlowDisconnect records a subscriber disconnected because its queue was full
+func (m *Metrics) SlowDisconnect() {
+	if m == nil {
+		return
+	}
+
+	m.disconnects.Inc()
+}
+
+// age returns the number of seconds since the rates were fetched, 0 until rates are loaded
+func (m *Metrics) age() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.fetched.IsZero() {
+		return 0
+	}
+
+	return time.Since(m.fetched).Seconds()
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
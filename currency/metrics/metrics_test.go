@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := New(reg)
+
+	m.ProviderFetch(time.Millisecond, nil)
+	m.ProviderFetch(time.Millisecond, errors.New("unavailable"))
+	m.RatesFetched(time.Now().Add(-time.Minute), true)
+	m.Subscribers(2)
+	m.AddSubscriptions(3)
+	m.AddSubscriptions(-1)
+	m.Broadcast()
+	m.SendFailure()
+	m.Dropped("drop_oldest", 2)
+	m.SlowDisconnect()
+	m.ProviderAttempt("ecb", errors.New("timeout"))
+	m.ActiveProvider("file", true)
+	m.CircuitOpen("ecb", true)
+
+	if v := testutil.ToFloat64(m.fetchErrors); v != 1 {
+		t.Fatalf("expected 1 fetch error, got %v", v)
+	}
+
+	if v := testutil.ToFloat64(m.stale); v != 1 {
+		t.Fatalf("expected stale rates, got %v", v)
+	}
+
+	if v := testutil.ToFloat64(m.subscriptions); v != 2 {
+		t.Fatalf("expected 2 subscriptions, got %v", v)
+	}
+
+	if v := testutil.ToFloat64(m.dropped.WithLabelValues("drop_oldest")); v != 2 {
+		t.Fatalf("expected 2 dropped messages, got %v", v)
+	}
+
+	if v := testutil.ToFloat64(m.circuitOpen.WithLabelValues("ecb")); v != 1 {
+		t.Fatalf("expected the ecb circuit to be open, got %v", v)
+	}
+
+	if a := m.age(); a < 60 {
+		t.Fatalf("expected age of at least 60s, got %v", a)
+	}
+
+	n, err := testutil.GatherAndCount(reg,
+		"currency_provider_fetch_duration_seconds",
+		"currency_rates_age_seconds",
+		"currency_subscribers",
+		"currency_ticks_broadcast_total",
+		"currency_send_failures_total",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the histogram has a series for each result
+	if n != 6 {
+		t.Fatalf("expected 6 series, got %d", n)
+	}
+}
+
+func TestNilMetrics(t *testing.T) {
+	var m *Metrics
+
+	// all methods are safe to call on nil metrics
+	m.ProviderFetch(time.Millisecond, nil)
+	m.RatesFetched(time.Now(), false)
+	m.Subscribers(1)
+	m.AddSubscriptions(1)
+	m.Broadcast()
+	m.SendFailure()
+	m.Dropped("coalesce", 1)
+	m.SlowDisconnect()
+	m.ProviderAttempt("ecb", nil)
+	m.ActiveProvider("ecb", true)
+	m.CircuitOpen("ecb", false)
+}
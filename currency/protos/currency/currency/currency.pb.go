@@ -10,6 +10,8 @@ import (
 	status "google.golang.org/genproto/googleapis/rpc/status"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -21,7 +23,58 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-// Currencies is an enum which represents the allowed/supported currencies for the API
+// Side is the side of a conversion taken by the client
+type Side int32
+
+const (
+	// SELL converts an amount the client sells, it uses the bid rate so the margin is subtracted
+	Side_SELL Side = 0
+	// BUY converts the price of an amount the client buys, e.g. at checkout, it
+	// uses the ask rate so the margin is added
+	Side_BUY Side = 1
+)
+
+// Enum value maps for Side.
+var (
+	Side_name = map[int32]string{
+		0: "SELL",
+		1: "BUY",
+	}
+	Side_value = map[string]int32{
+		"SELL": 0,
+		"BUY":  1,
+	}
+)
+
+func (x Side) Enum() *Side {
+	p := new(Side)
+	*p = x
+	return p
+}
+
+func (x Side) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Side) Descriptor() protoreflect.EnumDescriptor {
+	return file_currency_proto_enumTypes[0].Descriptor()
+}
+
+func (Side) Type() protoreflect.EnumType {
+	return &file_currency_proto_enumTypes[0]
+}
+
+func (x Side) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Side.Descriptor instead.
+func (Side) EnumDescriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{0}
+}
+
+// Currencies is an enum which represents the allowed/supported currencies for the API,
+// new currencies are not added to the enum, use the string currency codes to request them
 type Currencies int32
 
 const (
@@ -39,8 +92,10 @@ const (
 	Currencies_CHF Currencies = 11
 	Currencies_ISK Currencies = 12
 	Currencies_NOK Currencies = 13
-	Currencies_HRK Currencies = 14
-	Currencies_RUB Currencies = 15
+	// Deprecated: Marked as deprecated in currency.proto.
+	Currencies_HRK Currencies = 14 // no longer published by the ECB
+	// Deprecated: Marked as deprecated in currency.proto.
+	Currencies_RUB Currencies = 15 // no longer published by the ECB
 	Currencies_TRY Currencies = 16
 	Currencies_AUD Currencies = 17
 	Currencies_BRL Currencies = 18
@@ -145,11 +200,11 @@ func (x Currencies) String() string {
 }
 
 func (Currencies) Descriptor() protoreflect.EnumDescriptor {
-	return file_currency_proto_enumTypes[0].Descriptor()
+	return file_currency_proto_enumTypes[1].Descriptor()
 }
 
 func (Currencies) Type() protoreflect.EnumType {
-	return &file_currency_proto_enumTypes[0]
+	return &file_currency_proto_enumTypes[1]
 }
 
 func (x Currencies) Number() protoreflect.EnumNumber {
@@ -158,7 +213,7 @@ func (x Currencies) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use Currencies.Descriptor instead.
 func (Currencies) EnumDescriptor() ([]byte, []int) {
-	return file_currency_proto_rawDescGZIP(), []int{0}
+	return file_currency_proto_rawDescGZIP(), []int{1}
 }
 
 // RateRequest defines the request for a GetRate call
@@ -171,6 +226,21 @@ type RateRequest struct {
 	Base Currencies `protobuf:"varint,1,opt,name=Base,proto3,enum=Currencies" json:"Base,omitempty"`
 	// Destination is the destination currency code for the rate
 	Destination Currencies `protobuf:"varint,2,opt,name=Destination,proto3,enum=Currencies" json:"Destination,omitempty"`
+	// BaseCode is the ISO 4217 base currency code for the rate, when set it
+	// is used instead of Base and allows any currency with a rate to be requested
+	BaseCode string `protobuf:"bytes,3,opt,name=BaseCode,proto3" json:"BaseCode,omitempty"`
+	// DestinationCode is the ISO 4217 destination currency code for the rate,
+	// when set it is used instead of Destination
+	DestinationCode string `protobuf:"bytes,4,opt,name=DestinationCode,proto3" json:"DestinationCode,omitempty"`
+	// Profile is the name of the spread profile used for the bid and ask rates,
+	// when empty the bid and ask are the mid rate
+	Profile string `protobuf:"bytes,5,opt,name=Profile,proto3" json:"Profile,omitempty"`
+	// Unsubscribe removes the subscription for the rate instead of adding it,
+	// it is only used by SubscribeRates
+	Unsubscribe bool `protobuf:"varint,6,opt,name=Unsubscribe,proto3" json:"Unsubscribe,omitempty"`
+	// Conditions limits the updates sent for a subscription to alerts, when not
+	// set every change to the rate is sent. It is only used by SubscribeRates
+	Conditions *AlertConditions `protobuf:"bytes,7,opt,name=Conditions,proto3" json:"Conditions,omitempty"`
 }
 
 func (x *RateRequest) Reset() {
@@ -219,6 +289,41 @@ func (x *RateRequest) GetDestination() Currencies {
 	return Currencies_EUR
 }
 
+func (x *RateRequest) GetBaseCode() string {
+	if x != nil {
+		return x.BaseCode
+	}
+	return ""
+}
+
+func (x *RateRequest) GetDestinationCode() string {
+	if x != nil {
+		return x.DestinationCode
+	}
+	return ""
+}
+
+func (x *RateRequest) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *RateRequest) GetUnsubscribe() bool {
+	if x != nil {
+		return x.Unsubscribe
+	}
+	return false
+}
+
+func (x *RateRequest) GetConditions() *AlertConditions {
+	if x != nil {
+		return x.Conditions
+	}
+	return nil
+}
+
 // RateResponse is the response from a GetRate call, it contains
 // rate which is a floating point number and can be used to convert between the
 // two currencies specified in the request
@@ -231,8 +336,27 @@ type RateResponse struct {
 	Base Currencies `protobuf:"varint,1,opt,name=Base,proto3,enum=Currencies" json:"Base,omitempty"`
 	// Destination is the destination currency code for the rate
 	Destination Currencies `protobuf:"varint,2,opt,name=Destination,proto3,enum=Currencies" json:"Destination,omitempty"`
-	// Rate is the returned currency rate
+	// Rate is the returned currency rate, this is the mid rate
 	Rate float64 `protobuf:"fixed64,3,opt,name=Rate,proto3" json:"Rate,omitempty"`
+	// BaseCode is the ISO 4217 base currency code for the rate, Base is set to
+	// the zero value EUR when the currency is not part of the Currencies enum
+	BaseCode string `protobuf:"bytes,4,opt,name=BaseCode,proto3" json:"BaseCode,omitempty"`
+	// DestinationCode is the ISO 4217 destination currency code for the rate
+	DestinationCode string `protobuf:"bytes,5,opt,name=DestinationCode,proto3" json:"DestinationCode,omitempty"`
+	// AsOf is the time the rate took effect, the time the rates were fetched from
+	// the provider or simulated, or the publication date for historical rates
+	AsOf *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=AsOf,proto3" json:"AsOf,omitempty"`
+	// Source identifies where the rate came from, the name of the rate provider
+	// e.g. ecb, "snapshot" for rates loaded from the cache or "simulation"
+	Source string `protobuf:"bytes,7,opt,name=Source,proto3" json:"Source,omitempty"`
+	// Stale is true when the rate was loaded from the cache because the provider could not be reached
+	Stale bool `protobuf:"varint,8,opt,name=Stale,proto3" json:"Stale,omitempty"`
+	// Bid is the rate with the spread of the requested profile subtracted
+	Bid float64 `protobuf:"fixed64,9,opt,name=Bid,proto3" json:"Bid,omitempty"`
+	// Ask is the rate with the spread of the requested profile added
+	Ask float64 `protobuf:"fixed64,10,opt,name=Ask,proto3" json:"Ask,omitempty"`
+	// Profile is the name of the spread profile applied to the bid and ask
+	Profile string `protobuf:"bytes,11,opt,name=Profile,proto3" json:"Profile,omitempty"`
 }
 
 func (x *RateResponse) Reset() {
@@ -288,20 +412,81 @@ func (x *RateResponse) GetRate() float64 {
 	return 0
 }
 
-type StreamingRateResponse struct {
+func (x *RateResponse) GetBaseCode() string {
+	if x != nil {
+		return x.BaseCode
+	}
+	return ""
+}
+
+func (x *RateResponse) GetDestinationCode() string {
+	if x != nil {
+		return x.DestinationCode
+	}
+	return ""
+}
+
+func (x *RateResponse) GetAsOf() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AsOf
+	}
+	return nil
+}
+
+func (x *RateResponse) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *RateResponse) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *RateResponse) GetBid() float64 {
+	if x != nil {
+		return x.Bid
+	}
+	return 0
+}
+
+func (x *RateResponse) GetAsk() float64 {
+	if x != nil {
+		return x.Ask
+	}
+	return 0
+}
+
+func (x *RateResponse) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+// BatchRateRequest defines the request for a GetRates call
+type BatchRateRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Types that are assignable to Message:
-	//
-	//	*StreamingRateResponse_RateResponse
-	//	*StreamingRateResponse_Error
-	Message isStreamingRateResponse_Message `protobuf_oneof:"message"`
+	// Base is the base currency code for the rates
+	Base Currencies `protobuf:"varint,1,opt,name=Base,proto3,enum=Currencies" json:"Base,omitempty"`
+	// Destinations are the destination currency codes for the rates
+	Destinations []Currencies `protobuf:"varint,2,rep,packed,name=Destinations,proto3,enum=Currencies" json:"Destinations,omitempty"`
+	// BaseCode is the ISO 4217 base currency code, when set it is used instead of Base
+	BaseCode string `protobuf:"bytes,3,opt,name=BaseCode,proto3" json:"BaseCode,omitempty"`
+	// DestinationCodes are ISO 4217 destination currency codes, they are
+	// returned after the rates for Destinations
+	DestinationCodes []string `protobuf:"bytes,4,rep,name=DestinationCodes,proto3" json:"DestinationCodes,omitempty"`
 }
 
-func (x *StreamingRateResponse) Reset() {
-	*x = StreamingRateResponse{}
+func (x *BatchRateRequest) Reset() {
+	*x = BatchRateRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_currency_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -309,13 +494,13 @@ func (x *StreamingRateResponse) Reset() {
 	}
 }
 
-func (x *StreamingRateResponse) String() string {
+func (x *BatchRateRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StreamingRateResponse) ProtoMessage() {}
+func (*BatchRateRequest) ProtoMessage() {}
 
-func (x *StreamingRateResponse) ProtoReflect() protoreflect.Message {
+func (x *BatchRateRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_currency_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -327,175 +512,1603 @@ func (x *StreamingRateResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StreamingRateResponse.ProtoReflect.Descriptor instead.
-func (*StreamingRateResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use BatchRateRequest.ProtoReflect.Descriptor instead.
+func (*BatchRateRequest) Descriptor() ([]byte, []int) {
 	return file_currency_proto_rawDescGZIP(), []int{2}
 }
 
-func (m *StreamingRateResponse) GetMessage() isStreamingRateResponse_Message {
+func (x *BatchRateRequest) GetBase() Currencies {
+	if x != nil {
+		return x.Base
+	}
+	return Currencies_EUR
+}
+
+func (x *BatchRateRequest) GetDestinations() []Currencies {
+	if x != nil {
+		return x.Destinations
+	}
+	return nil
+}
+
+func (x *BatchRateRequest) GetBaseCode() string {
+	if x != nil {
+		return x.BaseCode
+	}
+	return ""
+}
+
+func (x *BatchRateRequest) GetDestinationCodes() []string {
+	if x != nil {
+		return x.DestinationCodes
+	}
+	return nil
+}
+
+// BatchRateResponse is the response from a GetRates call, all rates come from
+// the same set of rates and are in the same order as the requested destinations
+type BatchRateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rates []*BatchRate `protobuf:"bytes,1,rep,name=Rates,proto3" json:"Rates,omitempty"`
+}
+
+func (x *BatchRateResponse) Reset() {
+	*x = BatchRateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_currency_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchRateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchRateResponse) ProtoMessage() {}
+
+func (x *BatchRateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchRateResponse.ProtoReflect.Descriptor instead.
+func (*BatchRateResponse) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BatchRateResponse) GetRates() []*BatchRate {
+	if x != nil {
+		return x.Rates
+	}
+	return nil
+}
+
+// BatchRate contains either the rate for a destination or the error
+// which prevented the rate from being returned
+type BatchRate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Message:
+	//	*BatchRate_RateResponse
+	//	*BatchRate_Error
+	Message isBatchRate_Message `protobuf_oneof:"message"`
+}
+
+func (x *BatchRate) Reset() {
+	*x = BatchRate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_currency_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchRate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchRate) ProtoMessage() {}
+
+func (x *BatchRate) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchRate.ProtoReflect.Descriptor instead.
+func (*BatchRate) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{4}
+}
+
+func (m *BatchRate) GetMessage() isBatchRate_Message {
 	if m != nil {
 		return m.Message
 	}
 	return nil
 }
 
-func (x *StreamingRateResponse) GetRateResponse() *RateResponse {
-	if x, ok := x.GetMessage().(*StreamingRateResponse_RateResponse); ok {
+func (x *BatchRate) GetRateResponse() *RateResponse {
+	if x, ok := x.GetMessage().(*BatchRate_RateResponse); ok {
 		return x.RateResponse
 	}
 	return nil
 }
 
-func (x *StreamingRateResponse) GetError() *status.Status {
-	if x, ok := x.GetMessage().(*StreamingRateResponse_Error); ok {
+func (x *BatchRate) GetError() *status.Status {
+	if x, ok := x.GetMessage().(*BatchRate_Error); ok {
 		return x.Error
 	}
 	return nil
 }
 
-type isStreamingRateResponse_Message interface {
-	isStreamingRateResponse_Message()
+type isBatchRate_Message interface {
+	isBatchRate_Message()
 }
 
-type StreamingRateResponse_RateResponse struct {
+type BatchRate_RateResponse struct {
 	RateResponse *RateResponse `protobuf:"bytes,1,opt,name=rate_response,json=rateResponse,proto3,oneof"`
 }
 
-type StreamingRateResponse_Error struct {
+type BatchRate_Error struct {
 	Error *status.Status `protobuf:"bytes,2,opt,name=error,proto3,oneof"`
 }
 
-func (*StreamingRateResponse_RateResponse) isStreamingRateResponse_Message() {}
+func (*BatchRate_RateResponse) isBatchRate_Message() {}
 
-func (*StreamingRateResponse_Error) isStreamingRateResponse_Message() {}
+func (*BatchRate_Error) isBatchRate_Message() {}
 
-var File_currency_proto protoreflect.FileDescriptor
+// HistoricalRateRequest defines the request for a GetHistoricalRate call
+type HistoricalRateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-var file_currency_proto_rawDesc = []byte{
-	0x0a, 0x0e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x1a, 0x17, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x73, 0x74, 0x61,
-	0x74, 0x75, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x5d, 0x0a, 0x0b, 0x52, 0x61, 0x74,
-	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x04, 0x42, 0x61, 0x73, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0b, 0x2e, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63,
-	0x69, 0x65, 0x73, 0x52, 0x04, 0x42, 0x61, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x0b, 0x44, 0x65, 0x73,
-	0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0b,
-	0x2e, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x0b, 0x44, 0x65, 0x73,
-	0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x72, 0x0a, 0x0c, 0x52, 0x61, 0x74, 0x65,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x04, 0x42, 0x61, 0x73, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0b, 0x2e, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63,
-	0x69, 0x65, 0x73, 0x52, 0x04, 0x42, 0x61, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x0b, 0x44, 0x65, 0x73,
-	0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0b,
-	0x2e, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x0b, 0x44, 0x65, 0x73,
-	0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x52, 0x61, 0x74, 0x65,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x52, 0x61, 0x74, 0x65, 0x22, 0x84, 0x01, 0x0a,
-	0x15, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x34, 0x0a, 0x0d, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x72,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e,
-	0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x0c,
-	0x72, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a, 0x0a, 0x05,
-	0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x67, 0x6f,
-	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x48,
-	0x00, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x42, 0x09, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x2a, 0xb5, 0x02, 0x0a, 0x0a, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69,
-	0x65, 0x73, 0x12, 0x07, 0x0a, 0x03, 0x45, 0x55, 0x52, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03, 0x55,
-	0x53, 0x44, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x4a, 0x50, 0x59, 0x10, 0x02, 0x12, 0x07, 0x0a,
-	0x03, 0x42, 0x47, 0x4e, 0x10, 0x03, 0x12, 0x07, 0x0a, 0x03, 0x43, 0x5a, 0x4b, 0x10, 0x04, 0x12,
-	0x07, 0x0a, 0x03, 0x44, 0x4b, 0x4b, 0x10, 0x05, 0x12, 0x07, 0x0a, 0x03, 0x47, 0x42, 0x50, 0x10,
-	0x06, 0x12, 0x07, 0x0a, 0x03, 0x48, 0x55, 0x46, 0x10, 0x07, 0x12, 0x07, 0x0a, 0x03, 0x50, 0x4c,
-	0x4e, 0x10, 0x08, 0x12, 0x07, 0x0a, 0x03, 0x52, 0x4f, 0x4e, 0x10, 0x09, 0x12, 0x07, 0x0a, 0x03,
-	0x53, 0x45, 0x4b, 0x10, 0x0a, 0x12, 0x07, 0x0a, 0x03, 0x43, 0x48, 0x46, 0x10, 0x0b, 0x12, 0x07,
-	0x0a, 0x03, 0x49, 0x53, 0x4b, 0x10, 0x0c, 0x12, 0x07, 0x0a, 0x03, 0x4e, 0x4f, 0x4b, 0x10, 0x0d,
-	0x12, 0x07, 0x0a, 0x03, 0x48, 0x52, 0x4b, 0x10, 0x0e, 0x12, 0x07, 0x0a, 0x03, 0x52, 0x55, 0x42,
-	0x10, 0x0f, 0x12, 0x07, 0x0a, 0x03, 0x54, 0x52, 0x59, 0x10, 0x10, 0x12, 0x07, 0x0a, 0x03, 0x41,
-	0x55, 0x44, 0x10, 0x11, 0x12, 0x07, 0x0a, 0x03, 0x42, 0x52, 0x4c, 0x10, 0x12, 0x12, 0x07, 0x0a,
-	0x03, 0x43, 0x41, 0x44, 0x10, 0x13, 0x12, 0x07, 0x0a, 0x03, 0x43, 0x4e, 0x59, 0x10, 0x14, 0x12,
-	0x07, 0x0a, 0x03, 0x48, 0x4b, 0x44, 0x10, 0x15, 0x12, 0x07, 0x0a, 0x03, 0x49, 0x44, 0x52, 0x10,
-	0x16, 0x12, 0x07, 0x0a, 0x03, 0x49, 0x4c, 0x53, 0x10, 0x17, 0x12, 0x07, 0x0a, 0x03, 0x49, 0x4e,
-	0x52, 0x10, 0x18, 0x12, 0x07, 0x0a, 0x03, 0x4b, 0x52, 0x57, 0x10, 0x19, 0x12, 0x07, 0x0a, 0x03,
-	0x4d, 0x58, 0x4e, 0x10, 0x1a, 0x12, 0x07, 0x0a, 0x03, 0x4d, 0x59, 0x52, 0x10, 0x1b, 0x12, 0x07,
-	0x0a, 0x03, 0x4e, 0x5a, 0x44, 0x10, 0x1c, 0x12, 0x07, 0x0a, 0x03, 0x50, 0x48, 0x50, 0x10, 0x1d,
-	0x12, 0x07, 0x0a, 0x03, 0x53, 0x47, 0x44, 0x10, 0x1e, 0x12, 0x07, 0x0a, 0x03, 0x54, 0x48, 0x42,
-	0x10, 0x1f, 0x12, 0x07, 0x0a, 0x03, 0x5a, 0x41, 0x52, 0x10, 0x20, 0x32, 0x6e, 0x0a, 0x08, 0x43,
-	0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x26, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x52, 0x61,
-	0x74, 0x65, 0x12, 0x0c, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x0d, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x3a, 0x0a, 0x0e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x52, 0x61, 0x74, 0x65,
-	0x73, 0x12, 0x0c, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x16, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x52, 0x61, 0x74, 0x65, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x30, 0x01, 0x42, 0x0b, 0x5a, 0x09, 0x2f,
-	0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	// Request contains the currency codes for the rate
+	Request *RateRequest `protobuf:"bytes,1,opt,name=Request,proto3" json:"Request,omitempty"`
+	// Date is the date the rate applied on, when no rate was published on this
+	// date the most recent rate published before it is used
+	Date *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=Date,proto3" json:"Date,omitempty"`
 }
 
-var (
-	file_currency_proto_rawDescOnce sync.Once
-	file_currency_proto_rawDescData = file_currency_proto_rawDesc
-)
+func (x *HistoricalRateRequest) Reset() {
+	*x = HistoricalRateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_currency_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
 
-func file_currency_proto_rawDescGZIP() []byte {
-	file_currency_proto_rawDescOnce.Do(func() {
-		file_currency_proto_rawDescData = protoimpl.X.CompressGZIP(file_currency_proto_rawDescData)
-	})
-	return file_currency_proto_rawDescData
+func (x *HistoricalRateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var file_currency_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_currency_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
-var file_currency_proto_goTypes = []any{
-	(Currencies)(0),               // 0: Currencies
-	(*RateRequest)(nil),           // 1: RateRequest
-	(*RateResponse)(nil),          // 2: RateResponse
-	(*StreamingRateResponse)(nil), // 3: StreamingRateResponse
-	(*status.Status)(nil),         // 4: google.rpc.Status
+func (*HistoricalRateRequest) ProtoMessage() {}
+
+func (x *HistoricalRateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-var file_currency_proto_depIdxs = []int32{
-	0, // 0: RateRequest.Base:type_name -> Currencies
-	0, // 1: RateRequest.Destination:type_name -> Currencies
-	0, // 2: RateResponse.Base:type_name -> Currencies
-	0, // 3: RateResponse.Destination:type_name -> Currencies
-	2, // 4: StreamingRateResponse.rate_response:type_name -> RateResponse
-	4, // 5: StreamingRateResponse.error:type_name -> google.rpc.Status
-	1, // 6: Currency.GetRate:input_type -> RateRequest
-	1, // 7: Currency.SubscribeRates:input_type -> RateRequest
-	2, // 8: Currency.GetRate:output_type -> RateResponse
-	3, // 9: Currency.SubscribeRates:output_type -> StreamingRateResponse
-	8, // [8:10] is the sub-list for method output_type
-	6, // [6:8] is the sub-list for method input_type
-	6, // [6:6] is the sub-list for extension type_name
-	6, // [6:6] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+
+// Deprecated: Use HistoricalRateRequest.ProtoReflect.Descriptor instead.
+func (*HistoricalRateRequest) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{5}
 }
 
-func init() { file_currency_proto_init() }
-func file_currency_proto_init() {
-	if File_currency_proto != nil {
-		return
+func (x *HistoricalRateRequest) GetRequest() *RateRequest {
+	if x != nil {
+		return x.Request
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_currency_proto_msgTypes[0].Exporter = func(v any, i int) any {
-			switch v := v.(*RateRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_currency_proto_msgTypes[1].Exporter = func(v any, i int) any {
-			switch v := v.(*RateResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
+	return nil
+}
+
+func (x *HistoricalRateRequest) GetDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+// HistoricalRateResponse is the response from a GetHistoricalRate call
+type HistoricalRateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Response contains the rate which applied on the requested date
+	Response *RateResponse `protobuf:"bytes,1,opt,name=Response,proto3" json:"Response,omitempty"`
+	// Date is the date the returned rate was published
+	Date *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=Date,proto3" json:"Date,omitempty"`
+}
+
+func (x *HistoricalRateResponse) Reset() {
+	*x = HistoricalRateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_currency_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HistoricalRateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoricalRateResponse) ProtoMessage() {}
+
+func (x *HistoricalRateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoricalRateResponse.ProtoReflect.Descriptor instead.
+func (*HistoricalRateResponse) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *HistoricalRateResponse) GetResponse() *RateResponse {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+func (x *HistoricalRateResponse) GetDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+// Money is an exact decimal amount of money, the amount is the sum of the units
+// and nanos, for example -1.75 is represented as Units=-1 and Nanos=-750000000
+type Money struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Units are the whole units of the amount
+	Units int64 `protobuf:"varint,1,opt,name=Units,proto3" json:"Units,omitempty"`
+	// Nanos are the number of nano (10^-9) units of the amount, they must be
+	// between -999,999,999 and +999,999,999 and have the same sign as Units
+	Nanos int32 `protobuf:"varint,2,opt,name=Nanos,proto3" json:"Nanos,omitempty"`
+}
+
+func (x *Money) Reset() {
+	*x = Money{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_currency_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Money) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Money) ProtoMessage() {}
+
+func (x *Money) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Money.ProtoReflect.Descriptor instead.
+func (*Money) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Money) GetUnits() int64 {
+	if x != nil {
+		return x.Units
+	}
+	return 0
+}
+
+func (x *Money) GetNanos() int32 {
+	if x != nil {
+		return x.Nanos
+	}
+	return 0
+}
+
+// ConvertRequest defines the request for a Convert call
+type ConvertRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Amount is the amount of money in the base currency
+	Amount *Money `protobuf:"bytes,1,opt,name=Amount,proto3" json:"Amount,omitempty"`
+	// Base is the currency code of the amount
+	Base Currencies `protobuf:"varint,2,opt,name=Base,proto3,enum=Currencies" json:"Base,omitempty"`
+	// Destination is the currency code to convert the amount to
+	Destination Currencies `protobuf:"varint,3,opt,name=Destination,proto3,enum=Currencies" json:"Destination,omitempty"`
+	// BaseCode is the ISO 4217 code of the amount, when set it is used instead of Base
+	BaseCode string `protobuf:"bytes,4,opt,name=BaseCode,proto3" json:"BaseCode,omitempty"`
+	// DestinationCode is the ISO 4217 code to convert the amount to, when set
+	// it is used instead of Destination
+	DestinationCode string `protobuf:"bytes,5,opt,name=DestinationCode,proto3" json:"DestinationCode,omitempty"`
+	// Profile is the name of the spread profile, the amount is converted at the
+	// bid or ask rate of the profile depending on Side. When empty the mid rate is used
+	Profile string `protobuf:"bytes,6,opt,name=Profile,proto3" json:"Profile,omitempty"`
+	// Side is the side of the conversion taken by the client, it selects the bid
+	// or ask rate of the profile
+	Side Side `protobuf:"varint,7,opt,name=Side,proto3,enum=Side" json:"Side,omitempty"`
+}
+
+func (x *ConvertRequest) Reset() {
+	*x = ConvertRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_currency_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConvertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConvertRequest) ProtoMessage() {}
+
+func (x *ConvertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConvertRequest.ProtoReflect.Descriptor instead.
+func (*ConvertRequest) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ConvertRequest) GetAmount() *Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *ConvertRequest) GetBase() Currencies {
+	if x != nil {
+		return x.Base
+	}
+	return Currencies_EUR
+}
+
+func (x *ConvertRequest) GetDestination() Currencies {
+	if x != nil {
+		return x.Destination
+	}
+	return Currencies_EUR
+}
+
+func (x *ConvertRequest) GetBaseCode() string {
+	if x != nil {
+		return x.BaseCode
+	}
+	return ""
+}
+
+func (x *ConvertRequest) GetDestinationCode() string {
+	if x != nil {
+		return x.DestinationCode
+	}
+	return ""
+}
+
+func (x *ConvertRequest) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *ConvertRequest) GetSide() Side {
+	if x != nil {
+		return x.Side
+	}
+	return Side_SELL
+}
+
+// ConvertResponse is the response from a Convert call
+type ConvertResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Amount is the converted amount rounded to the minor units of the destination currency
+	Amount *Money `protobuf:"bytes,1,opt,name=Amount,proto3" json:"Amount,omitempty"`
+	// Base is the currency code of the original amount
+	Base Currencies `protobuf:"varint,2,opt,name=Base,proto3,enum=Currencies" json:"Base,omitempty"`
+	// Destination is the currency code of the converted amount
+	Destination Currencies `protobuf:"varint,3,opt,name=Destination,proto3,enum=Currencies" json:"Destination,omitempty"`
+	// Rate is the rate used for the conversion, the bid or ask rate for the side
+	// when a profile was requested
+	Rate float64 `protobuf:"fixed64,4,opt,name=Rate,proto3" json:"Rate,omitempty"`
+	// BaseCode is the ISO 4217 code of the original amount
+	BaseCode string `protobuf:"bytes,5,opt,name=BaseCode,proto3" json:"BaseCode,omitempty"`
+	// DestinationCode is the ISO 4217 code of the converted amount
+	DestinationCode string `protobuf:"bytes,6,opt,name=DestinationCode,proto3" json:"DestinationCode,omitempty"`
+}
+
+func (x *ConvertResponse) Reset() {
+	*x = ConvertResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_currency_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConvertResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConvertResponse) ProtoMessage() {}
+
+func (x *ConvertResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConvertResponse.ProtoReflect.Descriptor instead.
+func (*ConvertResponse) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ConvertResponse) GetAmount() *Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *ConvertResponse) GetBase() Currencies {
+	if x != nil {
+		return x.Base
+	}
+	return Currencies_EUR
+}
+
+func (x *ConvertResponse) GetDestination() Currencies {
+	if x != nil {
+		return x.Destination
+	}
+	return Currencies_EUR
+}
+
+func (x *ConvertResponse) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *ConvertResponse) GetBaseCode() string {
+	if x != nil {
+		return x.BaseCode
+	}
+	return ""
+}
+
+func (x *ConvertResponse) GetDestinationCode() string {
+	if x != nil {
+		return x.DestinationCode
+	}
+	return ""
+}
+
+// ListCurrenciesRequest defines the request for a ListCurrencies call
+type ListCurrenciesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListCurrenciesRequest) Reset() {
+	*x = ListCurrenciesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_currency_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListCurrenciesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCurrenciesRequest) ProtoMessage() {}
+
+func (x *ListCurrenciesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCurrenciesRequest.ProtoReflect.Descriptor instead.
+func (*ListCurrenciesRequest) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{10}
+}
+
+// ListCurrenciesResponse is the response from a ListCurrencies call
+type ListCurrenciesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Currencies are sorted by code
+	Currencies []*CurrencyInfo `protobuf:"bytes,1,rep,name=Currencies,proto3" json:"Currencies,omitempty"`
+}
+
+func (x *ListCurrenciesResponse) Reset() {
+	*x = ListCurrenciesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_currency_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListCurrenciesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCurrenciesResponse) ProtoMessage() {}
+
+func (x *ListCurrenciesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCurrenciesResponse.ProtoReflect.Descriptor instead.
+func (*ListCurrenciesResponse) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListCurrenciesResponse) GetCurrencies() []*CurrencyInfo {
+	if x != nil {
+		return x.Currencies
+	}
+	return nil
+}
+
+// CurrencyInfo contains the ISO 4217 details for a currency
+type CurrencyInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Code is the alphabetic currency code, e.g. EUR
+	Code string `protobuf:"bytes,1,opt,name=Code,proto3" json:"Code,omitempty"`
+	// Name is the name of the currency, e.g. Euro
+	Name string `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+	// NumericCode is the three digit numeric currency code, e.g. 978
+	NumericCode string `protobuf:"bytes,3,opt,name=NumericCode,proto3" json:"NumericCode,omitempty"`
+	// MinorUnits is the number of decimal digits in the minor unit of the currency
+	MinorUnits int32 `protobuf:"varint,4,opt,name=MinorUnits,proto3" json:"MinorUnits,omitempty"`
+	// Symbol is the symbol used for the currency, e.g. €
+	Symbol string `protobuf:"bytes,5,opt,name=Symbol,proto3" json:"Symbol,omitempty"`
+}
+
+func (x *CurrencyInfo) Reset() {
+	*x = CurrencyInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_currency_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CurrencyInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CurrencyInfo) ProtoMessage() {}
+
+func (x *CurrencyInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CurrencyInfo.ProtoReflect.Descriptor instead.
+func (*CurrencyInfo) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CurrencyInfo) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *CurrencyInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CurrencyInfo) GetNumericCode() string {
+	if x != nil {
+		return x.NumericCode
+	}
+	return ""
+}
+
+func (x *CurrencyInfo) GetMinorUnits() int32 {
+	if x != nil {
+		return x.MinorUnits
+	}
+	return 0
+}
+
+func (x *CurrencyInfo) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+// AlertConditions control when an update for a subscription is sent. An update is
+// sent when any of the MinChangePercent, Above or Below conditions fires, when none
+// of them are set every change fires. MinInterval holds back updates which fire too
+// soon after the previous update, the latest held back update is sent once the
+// interval has passed. Conditions compare the mid rate against the rate when the
+// subscription was created or the last update was sent
+type AlertConditions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// MinChangePercent fires when the rate has moved by at least this
+	// percentage since the last update was sent, e.g. 0.5 is 0.5%
+	MinChangePercent float64 `protobuf:"fixed64,1,opt,name=MinChangePercent,proto3" json:"MinChangePercent,omitempty"`
+	// Above fires when the rate rises above the threshold
+	Above float64 `protobuf:"fixed64,2,opt,name=Above,proto3" json:"Above,omitempty"`
+	// Below fires when the rate falls below the threshold
+	Below float64 `protobuf:"fixed64,3,opt,name=Below,proto3" json:"Below,omitempty"`
+	// MinInterval is the minimum time between updates
+	MinInterval *durationpb.Duration `protobuf:"bytes,4,opt,name=MinInterval,proto3" json:"MinInterval,omitempty"`
+}
+
+func (x *AlertConditions) Reset() {
+	*x = AlertConditions{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_currency_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AlertConditions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertConditions) ProtoMessage() {}
+
+func (x *AlertConditions) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertConditions.ProtoReflect.Descriptor instead.
+func (*AlertConditions) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *AlertConditions) GetMinChangePercent() float64 {
+	if x != nil {
+		return x.MinChangePercent
+	}
+	return 0
+}
+
+func (x *AlertConditions) GetAbove() float64 {
+	if x != nil {
+		return x.Above
+	}
+	return 0
+}
+
+func (x *AlertConditions) GetBelow() float64 {
+	if x != nil {
+		return x.Below
+	}
+	return 0
+}
+
+func (x *AlertConditions) GetMinInterval() *durationpb.Duration {
+	if x != nil {
+		return x.MinInterval
+	}
+	return nil
+}
+
+// StreamingRateResponse is a message sent to SubscribeRates clients, updated
+// rates carry the same timestamp and provenance as a GetRate response
+type StreamingRateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Message:
+	//	*StreamingRateResponse_RateResponse
+	//	*StreamingRateResponse_Error
+	Message isStreamingRateResponse_Message `protobuf_oneof:"message"`
+}
+
+func (x *StreamingRateResponse) Reset() {
+	*x = StreamingRateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_currency_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamingRateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamingRateResponse) ProtoMessage() {}
+
+func (x *StreamingRateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamingRateResponse.ProtoReflect.Descriptor instead.
+func (*StreamingRateResponse) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{14}
+}
+
+func (m *StreamingRateResponse) GetMessage() isStreamingRateResponse_Message {
+	if m != nil {
+		return m.Message
+	}
+	return nil
+}
+
+func (x *StreamingRateResponse) GetRateResponse() *RateResponse {
+	if x, ok := x.GetMessage().(*StreamingRateResponse_RateResponse); ok {
+		return x.RateResponse
+	}
+	return nil
+}
+
+func (x *StreamingRateResponse) GetError() *status.Status {
+	if x, ok := x.GetMessage().(*StreamingRateResponse_Error); ok {
+		return x.Error
+	}
+	return nil
+}
+
+type isStreamingRateResponse_Message interface {
+	isStreamingRateResponse_Message()
+}
+
+type StreamingRateResponse_RateResponse struct {
+	RateResponse *RateResponse `protobuf:"bytes,1,opt,name=rate_response,json=rateResponse,proto3,oneof"`
+}
+
+type StreamingRateResponse_Error struct {
+	Error *status.Status `protobuf:"bytes,2,opt,name=error,proto3,oneof"`
+}
+
+func (*StreamingRateResponse_RateResponse) isStreamingRateResponse_Message() {}
+
+func (*StreamingRateResponse_Error) isStreamingRateResponse_Message() {}
+
+// CandlesRequest defines the request for a GetCandles call
+type CandlesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Pair is the currency pair written as BASE/DEST, e.g. EUR/USD
+	Pair string `protobuf:"bytes,1,opt,name=Pair,proto3" json:"Pair,omitempty"`
+	// Resolution is the time covered by each candle [1m, 1h, 1d]
+	Resolution string `protobuf:"bytes,2,opt,name=Resolution,proto3" json:"Resolution,omitempty"`
+	// From is the start of the range, candles which contain this time are included
+	From *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=From,proto3" json:"From,omitempty"`
+	// To is the end of the range, when not set the range ends now
+	To *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=To,proto3" json:"To,omitempty"`
+}
+
+func (x *CandlesRequest) Reset() {
+	*x = CandlesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_currency_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CandlesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CandlesRequest) ProtoMessage() {}
+
+func (x *CandlesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CandlesRequest.ProtoReflect.Descriptor instead.
+func (*CandlesRequest) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CandlesRequest) GetPair() string {
+	if x != nil {
+		return x.Pair
+	}
+	return ""
+}
+
+func (x *CandlesRequest) GetResolution() string {
+	if x != nil {
+		return x.Resolution
+	}
+	return ""
+}
+
+func (x *CandlesRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *CandlesRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+// CandlesResponse is the response from a GetCandles call
+type CandlesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pair       string `protobuf:"bytes,1,opt,name=Pair,proto3" json:"Pair,omitempty"`
+	Resolution string `protobuf:"bytes,2,opt,name=Resolution,proto3" json:"Resolution,omitempty"`
+	// Candles are sorted by start time, the last candle is still open
+	// when the current time is within its period
+	Candles []*Candle `protobuf:"bytes,3,rep,name=Candles,proto3" json:"Candles,omitempty"`
+}
+
+func (x *CandlesResponse) Reset() {
+	*x = CandlesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_currency_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CandlesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CandlesResponse) ProtoMessage() {}
+
+func (x *CandlesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CandlesResponse.ProtoReflect.Descriptor instead.
+func (*CandlesResponse) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CandlesResponse) GetPair() string {
+	if x != nil {
+		return x.Pair
+	}
+	return ""
+}
+
+func (x *CandlesResponse) GetResolution() string {
+	if x != nil {
+		return x.Resolution
+	}
+	return ""
+}
+
+func (x *CandlesResponse) GetCandles() []*Candle {
+	if x != nil {
+		return x.Candles
+	}
+	return nil
+}
+
+// Candle contains the rates for a currency pair during a period, the
+// rates are mid rates
+type Candle struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Start is the start of the period, periods start on UTC boundaries
+	Start *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=Start,proto3" json:"Start,omitempty"`
+	Open  float64                `protobuf:"fixed64,2,opt,name=Open,proto3" json:"Open,omitempty"`
+	High  float64                `protobuf:"fixed64,3,opt,name=High,proto3" json:"High,omitempty"`
+	Low   float64                `protobuf:"fixed64,4,opt,name=Low,proto3" json:"Low,omitempty"`
+	Close float64                `protobuf:"fixed64,5,opt,name=Close,proto3" json:"Close,omitempty"`
+}
+
+func (x *Candle) Reset() {
+	*x = Candle{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_currency_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Candle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Candle) ProtoMessage() {}
+
+func (x *Candle) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Candle.ProtoReflect.Descriptor instead.
+func (*Candle) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Candle) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *Candle) GetOpen() float64 {
+	if x != nil {
+		return x.Open
+	}
+	return 0
+}
+
+func (x *Candle) GetHigh() float64 {
+	if x != nil {
+		return x.High
+	}
+	return 0
+}
+
+func (x *Candle) GetLow() float64 {
+	if x != nil {
+		return x.Low
+	}
+	return 0
+}
+
+func (x *Candle) GetClose() float64 {
+	if x != nil {
+		return x.Close
+	}
+	return 0
+}
+
+var File_currency_proto protoreflect.FileDescriptor
+
+var file_currency_proto_rawDesc = []byte{
+	0x0a, 0x0e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x1a, 0x17, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x91, 0x02, 0x0a, 0x0b, 0x52,
+	0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x04, 0x42, 0x61,
+	0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0b, 0x2e, 0x43, 0x75, 0x72, 0x72, 0x65,
+	0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x04, 0x42, 0x61, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x0b, 0x44,
+	0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x0b, 0x2e, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x0b, 0x44,
+	0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x42, 0x61,
+	0x73, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x42, 0x61,
+	0x73, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0f, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65,
+	0x12, 0x18, 0x0a, 0x07, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x55, 0x6e,
+	0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0b, 0x55, 0x6e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x12, 0x30, 0x0a, 0x0a,
+	0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x10, 0x2e, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x52, 0x0a, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xd4,
+	0x02, 0x0a, 0x0c, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x1f, 0x0a, 0x04, 0x42, 0x61, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0b, 0x2e,
+	0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x04, 0x42, 0x61, 0x73, 0x65,
+	0x12, 0x2d, 0x0a, 0x0b, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0b, 0x2e, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69,
+	0x65, 0x73, 0x52, 0x0b, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x12, 0x0a, 0x04, 0x52, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x52,
+	0x61, 0x74, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x42, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x42, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x12,
+	0x28, 0x0a, 0x0f, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f,
+	0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x2e, 0x0a, 0x04, 0x41, 0x73, 0x4f,
+	0x66, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x04, 0x41, 0x73, 0x4f, 0x66, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x53, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x6c, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x05, 0x53, 0x74, 0x61, 0x6c, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x42, 0x69, 0x64, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x42, 0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x41, 0x73, 0x6b,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x41, 0x73, 0x6b, 0x12, 0x18, 0x0a, 0x07, 0x50,
+	0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x50, 0x72,
+	0x6f, 0x66, 0x69, 0x6c, 0x65, 0x22, 0xac, 0x01, 0x0a, 0x10, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52,
+	0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x04, 0x42, 0x61,
+	0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0b, 0x2e, 0x43, 0x75, 0x72, 0x72, 0x65,
+	0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x04, 0x42, 0x61, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x0c, 0x44,
+	0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0e, 0x32, 0x0b, 0x2e, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x0c,
+	0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1a, 0x0a, 0x08,
+	0x42, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x42, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x2a, 0x0a, 0x10, 0x44, 0x65, 0x73, 0x74,
+	0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x10, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43,
+	0x6f, 0x64, 0x65, 0x73, 0x22, 0x35, 0x0a, 0x11, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x61, 0x74,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x20, 0x0a, 0x05, 0x52, 0x61, 0x74,
+	0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x52, 0x61, 0x74, 0x65, 0x52, 0x05, 0x52, 0x61, 0x74, 0x65, 0x73, 0x22, 0x78, 0x0a, 0x09, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x52, 0x61, 0x74, 0x65, 0x12, 0x34, 0x0a, 0x0d, 0x72, 0x61, 0x74, 0x65,
+	0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0d, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00,
+	0x52, 0x0c, 0x72, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a,
+	0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x48, 0x00, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x42, 0x09, 0x0a, 0x07, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x6f, 0x0a, 0x15, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x69,
+	0x63, 0x61, 0x6c, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26,
+	0x0a, 0x07, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0c, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x07, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2e, 0x0a, 0x04, 0x44, 0x61, 0x74, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x04, 0x44, 0x61, 0x74, 0x65, 0x22, 0x73, 0x0a, 0x16, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72,
+	0x69, 0x63, 0x61, 0x6c, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x29, 0x0a, 0x08, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x52, 0x08, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x04, 0x44,
+	0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x44, 0x61, 0x74, 0x65, 0x22, 0x33, 0x0a, 0x05, 0x4d,
+	0x6f, 0x6e, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x05, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x4e, 0x61,
+	0x6e, 0x6f, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x4e, 0x61, 0x6e, 0x6f, 0x73,
+	0x22, 0xfb, 0x01, 0x0a, 0x0e, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x06, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x06, 0x2e, 0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x52, 0x06, 0x41, 0x6d, 0x6f,
+	0x75, 0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x04, 0x42, 0x61, 0x73, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x0b, 0x2e, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x04,
+	0x42, 0x61, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x0b, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0b, 0x2e, 0x43, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x0b, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x42, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x42, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x12,
+	0x28, 0x0a, 0x0f, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f,
+	0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x50, 0x72, 0x6f,
+	0x66, 0x69, 0x6c, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x50, 0x72, 0x6f, 0x66,
+	0x69, 0x6c, 0x65, 0x12, 0x19, 0x0a, 0x04, 0x53, 0x69, 0x64, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x05, 0x2e, 0x53, 0x69, 0x64, 0x65, 0x52, 0x04, 0x53, 0x69, 0x64, 0x65, 0x22, 0xdb,
+	0x01, 0x0a, 0x0f, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x1e, 0x0a, 0x06, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x06, 0x2e, 0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x52, 0x06, 0x41, 0x6d, 0x6f, 0x75,
+	0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x04, 0x42, 0x61, 0x73, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x0b, 0x2e, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x04, 0x42,
+	0x61, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x0b, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0b, 0x2e, 0x43, 0x75, 0x72, 0x72, 0x65,
+	0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x0b, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x52, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x04, 0x52, 0x61, 0x74, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x42, 0x61, 0x73, 0x65, 0x43, 0x6f,
+	0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x42, 0x61, 0x73, 0x65, 0x43, 0x6f,
+	0x64, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x44, 0x65, 0x73,
+	0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x22, 0x17, 0x0a, 0x15,
+	0x4c, 0x69, 0x73, 0x74, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x47, 0x0a, 0x16, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x75, 0x72,
+	0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x2d, 0x0a, 0x0a, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x49, 0x6e,
+	0x66, 0x6f, 0x52, 0x0a, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x22, 0x90,
+	0x01, 0x0a, 0x0c, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x49, 0x6e, 0x66, 0x6f, 0x12,
+	0x12, 0x0a, 0x04, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x43,
+	0x6f, 0x64, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x4e, 0x75, 0x6d, 0x65, 0x72,
+	0x69, 0x63, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x4e, 0x75,
+	0x6d, 0x65, 0x72, 0x69, 0x63, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x4d, 0x69, 0x6e,
+	0x6f, 0x72, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x4d,
+	0x69, 0x6e, 0x6f, 0x72, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x79, 0x6d,
+	0x62, 0x6f, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x53, 0x79, 0x6d, 0x62, 0x6f,
+	0x6c, 0x22, 0xa6, 0x01, 0x0a, 0x0f, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x43, 0x6f, 0x6e, 0x64, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x2a, 0x0a, 0x10, 0x4d, 0x69, 0x6e, 0x43, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x10, 0x4d, 0x69, 0x6e, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e,
+	0x74, 0x12, 0x14, 0x0a, 0x05, 0x41, 0x62, 0x6f, 0x76, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x05, 0x41, 0x62, 0x6f, 0x76, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x42, 0x65, 0x6c, 0x6f, 0x77,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x42, 0x65, 0x6c, 0x6f, 0x77, 0x12, 0x3b, 0x0a,
+	0x0b, 0x4d, 0x69, 0x6e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x4d,
+	0x69, 0x6e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x22, 0x84, 0x01, 0x0a, 0x15, 0x53,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x34, 0x0a, 0x0d, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x72, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x52, 0x61,
+	0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x0c, 0x72, 0x61,
+	0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a, 0x0a, 0x05, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x48, 0x00, 0x52,
+	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x42, 0x09, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x22, 0xa0, 0x01, 0x0a, 0x0e, 0x43, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x50, 0x61, 0x69, 0x72, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x50, 0x61, 0x69, 0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x52, 0x65, 0x73, 0x6f,
+	0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x52, 0x65,
+	0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2e, 0x0a, 0x04, 0x46, 0x72, 0x6f, 0x6d,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x04, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x2a, 0x0a, 0x02, 0x54, 0x6f, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x02, 0x54, 0x6f, 0x22, 0x68, 0x0a, 0x0f, 0x43, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x50, 0x61, 0x69, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x50, 0x61, 0x69, 0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x52,
+	0x65, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0a, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x21, 0x0a, 0x07, 0x43,
+	0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x07, 0x2e, 0x43,
+	0x61, 0x6e, 0x64, 0x6c, 0x65, 0x52, 0x07, 0x43, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x22, 0x8a,
+	0x01, 0x0a, 0x06, 0x43, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x12, 0x30, 0x0a, 0x05, 0x53, 0x74, 0x61,
+	0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x4f,
+	0x70, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x4f, 0x70, 0x65, 0x6e, 0x12,
+	0x12, 0x0a, 0x04, 0x48, 0x69, 0x67, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x48,
+	0x69, 0x67, 0x68, 0x12, 0x10, 0x0a, 0x03, 0x4c, 0x6f, 0x77, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x03, 0x4c, 0x6f, 0x77, 0x12, 0x14, 0x0a, 0x05, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x2a, 0x19, 0x0a, 0x04, 0x53,
+	0x69, 0x64, 0x65, 0x12, 0x08, 0x0a, 0x04, 0x53, 0x45, 0x4c, 0x4c, 0x10, 0x00, 0x12, 0x07, 0x0a,
+	0x03, 0x42, 0x55, 0x59, 0x10, 0x01, 0x2a, 0xbd, 0x02, 0x0a, 0x0a, 0x43, 0x75, 0x72, 0x72, 0x65,
+	0x6e, 0x63, 0x69, 0x65, 0x73, 0x12, 0x07, 0x0a, 0x03, 0x45, 0x55, 0x52, 0x10, 0x00, 0x12, 0x07,
+	0x0a, 0x03, 0x55, 0x53, 0x44, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x4a, 0x50, 0x59, 0x10, 0x02,
+	0x12, 0x07, 0x0a, 0x03, 0x42, 0x47, 0x4e, 0x10, 0x03, 0x12, 0x07, 0x0a, 0x03, 0x43, 0x5a, 0x4b,
+	0x10, 0x04, 0x12, 0x07, 0x0a, 0x03, 0x44, 0x4b, 0x4b, 0x10, 0x05, 0x12, 0x07, 0x0a, 0x03, 0x47,
+	0x42, 0x50, 0x10, 0x06, 0x12, 0x07, 0x0a, 0x03, 0x48, 0x55, 0x46, 0x10, 0x07, 0x12, 0x07, 0x0a,
+	0x03, 0x50, 0x4c, 0x4e, 0x10, 0x08, 0x12, 0x07, 0x0a, 0x03, 0x52, 0x4f, 0x4e, 0x10, 0x09, 0x12,
+	0x07, 0x0a, 0x03, 0x53, 0x45, 0x4b, 0x10, 0x0a, 0x12, 0x07, 0x0a, 0x03, 0x43, 0x48, 0x46, 0x10,
+	0x0b, 0x12, 0x07, 0x0a, 0x03, 0x49, 0x53, 0x4b, 0x10, 0x0c, 0x12, 0x07, 0x0a, 0x03, 0x4e, 0x4f,
+	0x4b, 0x10, 0x0d, 0x12, 0x0b, 0x0a, 0x03, 0x48, 0x52, 0x4b, 0x10, 0x0e, 0x1a, 0x02, 0x08, 0x01,
+	0x12, 0x0b, 0x0a, 0x03, 0x52, 0x55, 0x42, 0x10, 0x0f, 0x1a, 0x02, 0x08, 0x01, 0x12, 0x07, 0x0a,
+	0x03, 0x54, 0x52, 0x59, 0x10, 0x10, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x55, 0x44, 0x10, 0x11, 0x12,
+	0x07, 0x0a, 0x03, 0x42, 0x52, 0x4c, 0x10, 0x12, 0x12, 0x07, 0x0a, 0x03, 0x43, 0x41, 0x44, 0x10,
+	0x13, 0x12, 0x07, 0x0a, 0x03, 0x43, 0x4e, 0x59, 0x10, 0x14, 0x12, 0x07, 0x0a, 0x03, 0x48, 0x4b,
+	0x44, 0x10, 0x15, 0x12, 0x07, 0x0a, 0x03, 0x49, 0x44, 0x52, 0x10, 0x16, 0x12, 0x07, 0x0a, 0x03,
+	0x49, 0x4c, 0x53, 0x10, 0x17, 0x12, 0x07, 0x0a, 0x03, 0x49, 0x4e, 0x52, 0x10, 0x18, 0x12, 0x07,
+	0x0a, 0x03, 0x4b, 0x52, 0x57, 0x10, 0x19, 0x12, 0x07, 0x0a, 0x03, 0x4d, 0x58, 0x4e, 0x10, 0x1a,
+	0x12, 0x07, 0x0a, 0x03, 0x4d, 0x59, 0x52, 0x10, 0x1b, 0x12, 0x07, 0x0a, 0x03, 0x4e, 0x5a, 0x44,
+	0x10, 0x1c, 0x12, 0x07, 0x0a, 0x03, 0x50, 0x48, 0x50, 0x10, 0x1d, 0x12, 0x07, 0x0a, 0x03, 0x53,
+	0x47, 0x44, 0x10, 0x1e, 0x12, 0x07, 0x0a, 0x03, 0x54, 0x48, 0x42, 0x10, 0x1f, 0x12, 0x07, 0x0a,
+	0x03, 0x5a, 0x41, 0x52, 0x10, 0x20, 0x32, 0x89, 0x03, 0x0a, 0x08, 0x43, 0x75, 0x72, 0x72, 0x65,
+	0x6e, 0x63, 0x79, 0x12, 0x26, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x52, 0x61, 0x74, 0x65, 0x12, 0x0c,
+	0x2e, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0d, 0x2e, 0x52,
+	0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x08, 0x47,
+	0x65, 0x74, 0x52, 0x61, 0x74, 0x65, 0x73, 0x12, 0x11, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52,
+	0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x42, 0x61, 0x74,
+	0x63, 0x68, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x44,
+	0x0a, 0x11, 0x47, 0x65, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x69, 0x63, 0x61, 0x6c, 0x52,
+	0x61, 0x74, 0x65, 0x12, 0x16, 0x2e, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x69, 0x63, 0x61, 0x6c,
+	0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x48, 0x69,
+	0x73, 0x74, 0x6f, 0x72, 0x69, 0x63, 0x61, 0x6c, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x07, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x12,
+	0x0f, 0x2e, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x10, 0x2e, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x41, 0x0a, 0x0e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e,
+	0x63, 0x69, 0x65, 0x73, 0x12, 0x16, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x75, 0x72, 0x72, 0x65,
+	0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x0e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
+	0x62, 0x65, 0x52, 0x61, 0x74, 0x65, 0x73, 0x12, 0x0c, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e,
+	0x67, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x30,
+	0x01, 0x12, 0x2f, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x43, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x12,
+	0x0f, 0x2e, 0x43, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x10, 0x2e, 0x43, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x42, 0x0b, 0x5a, 0x09, 0x2f, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_currency_proto_rawDescOnce sync.Once
+	file_currency_proto_rawDescData = file_currency_proto_rawDesc
+)
+
+func file_currency_proto_rawDescGZIP() []byte {
+	file_currency_proto_rawDescOnce.Do(func() {
+		file_currency_proto_rawDescData = protoimpl.X.CompressGZIP(file_currency_proto_rawDescData)
+	})
+	return file_currency_proto_rawDescData
+}
+
+var file_currency_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_currency_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_currency_proto_goTypes = []any{
+	(Side)(0),                      // 0: Side
+	(Currencies)(0),                // 1: Currencies
+	(*RateRequest)(nil),            // 2: RateRequest
+	(*RateResponse)(nil),           // 3: RateResponse
+	(*BatchRateRequest)(nil),       // 4: BatchRateRequest
+	(*BatchRateResponse)(nil),      // 5: BatchRateResponse
+	(*BatchRate)(nil),              // 6: BatchRate
+	(*HistoricalRateRequest)(nil),  // 7: HistoricalRateRequest
+	(*HistoricalRateResponse)(nil), // 8: HistoricalRateResponse
+	(*Money)(nil),                  // 9: Money
+	(*ConvertRequest)(nil),         // 10: ConvertRequest
+	(*ConvertResponse)(nil),        // 11: ConvertResponse
+	(*ListCurrenciesRequest)(nil),  // 12: ListCurrenciesRequest
+	(*ListCurrenciesResponse)(nil), // 13: ListCurrenciesResponse
+	(*CurrencyInfo)(nil),           // 14: CurrencyInfo
+	(*AlertConditions)(nil),        // 15: AlertConditions
+	(*StreamingRateResponse)(nil),  // 16: StreamingRateResponse
+	(*CandlesRequest)(nil),         // 17: CandlesRequest
+	(*CandlesResponse)(nil),        // 18: CandlesResponse
+	(*Candle)(nil),                 // 19: Candle
+	(*timestamppb.Timestamp)(nil),  // 20: google.protobuf.Timestamp
+	(*status.Status)(nil),          // 21: google.rpc.Status
+	(*durationpb.Duration)(nil),    // 22: google.protobuf.Duration
+}
+var file_currency_proto_depIdxs = []int32{
+	1,  // 0: RateRequest.Base:type_name -> Currencies
+	1,  // 1: RateRequest.Destination:type_name -> Currencies
+	15, // 2: RateRequest.Conditions:type_name -> AlertConditions
+	1,  // 3: RateResponse.Base:type_name -> Currencies
+	1,  // 4: RateResponse.Destination:type_name -> Currencies
+	20, // 5: RateResponse.AsOf:type_name -> google.protobuf.Timestamp
+	1,  // 6: BatchRateRequest.Base:type_name -> Currencies
+	1,  // 7: BatchRateRequest.Destinations:type_name -> Currencies
+	6,  // 8: BatchRateResponse.Rates:type_name -> BatchRate
+	3,  // 9: BatchRate.rate_response:type_name -> RateResponse
+	21, // 10: BatchRate.error:type_name -> google.rpc.Status
+	2,  // 11: HistoricalRateRequest.Request:type_name -> RateRequest
+	20, // 12: HistoricalRateRequest.Date:type_name -> google.protobuf.Timestamp
+	3,  // 13: HistoricalRateResponse.Response:type_name -> RateResponse
+	20, // 14: HistoricalRateResponse.Date:type_name -> google.protobuf.Timestamp
+	9,  // 15: ConvertRequest.Amount:type_name -> Money
+	1,  // 16: ConvertRequest.Base:type_name -> Currencies
+	1,  // 17: ConvertRequest.Destination:type_name -> Currencies
+	0,  // 18: ConvertRequest.Side:type_name -> Side
+	9,  // 19: ConvertResponse.Amount:type_name -> Money
+	1,  // 20: ConvertResponse.Base:type_name -> Currencies
+	1,  // 21: ConvertResponse.Destination:type_name -> Currencies
+	14, // 22: ListCurrenciesResponse.Currencies:type_name -> CurrencyInfo
+	22, // 23: AlertConditions.MinInterval:type_name -> google.protobuf.Duration
+	3,  // 24: StreamingRateResponse.rate_response:type_name -> RateResponse
+	21, // 25: StreamingRateResponse.error:type_name -> google.rpc.Status
+	20, // 26: CandlesRequest.From:type_name -> google.protobuf.Timestamp
+	20, // 27: CandlesRequest.To:type_name -> google.protobuf.Timestamp
+	19, // 28: CandlesResponse.Candles:type_name -> Candle
+	20, // 29: Candle.Start:type_name -> google.protobuf.Timestamp
+	2,  // 30: Currency.GetRate:input_type -> RateRequest
+	4,  // 31: Currency.GetRates:input_type -> BatchRateRequest
+	7,  // 32: Currency.GetHistoricalRate:input_type -> HistoricalRateRequest
+	10, // 33: Currency.Convert:input_type -> ConvertRequest
+	12, // 34: Currency.ListCurrencies:input_type -> ListCurrenciesRequest
+	2,  // 35: Currency.SubscribeRates:input_type -> RateRequest
+	17, // 36: Currency.GetCandles:input_type -> CandlesRequest
+	3,  // 37: Currency.GetRate:output_type -> RateResponse
+	5,  // 38: Currency.GetRates:output_type -> BatchRateResponse
+	8,  // 39: Currency.GetHistoricalRate:output_type -> HistoricalRateResponse
+	11, // 40: Currency.Convert:output_type -> ConvertResponse
+	13, // 41: Currency.ListCurrencies:output_type -> ListCurrenciesResponse
+	16, // 42: Currency.SubscribeRates:output_type -> StreamingRateResponse
+	18, // 43: Currency.GetCandles:output_type -> CandlesResponse
+	37, // [37:44] is the sub-list for method output_type
+	30, // [30:37] is the sub-list for method input_type
+	30, // [30:30] is the sub-list for extension type_name
+	30, // [30:30] is the sub-list for extension extendee
+	0,  // [0:30] is the sub-list for field type_name
+}
+
+func init() { file_currency_proto_init() }
+func file_currency_proto_init() {
+	if File_currency_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_currency_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*RateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_currency_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*RateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
 			}
 		}
 		file_currency_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*BatchRateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_currency_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*BatchRateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_currency_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*BatchRate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_currency_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*HistoricalRateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_currency_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*HistoricalRateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_currency_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*Money); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_currency_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*ConvertRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_currency_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*ConvertResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_currency_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*ListCurrenciesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_currency_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*ListCurrenciesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_currency_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*CurrencyInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_currency_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*AlertConditions); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_currency_proto_msgTypes[14].Exporter = func(v any, i int) any {
 			switch v := v.(*StreamingRateResponse); i {
 			case 0:
 				return &v.state
@@ -507,8 +2120,48 @@ func file_currency_proto_init() {
 				return nil
 			}
 		}
+		file_currency_proto_msgTypes[15].Exporter = func(v any, i int) any {
+			switch v := v.(*CandlesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_currency_proto_msgTypes[16].Exporter = func(v any, i int) any {
+			switch v := v.(*CandlesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_currency_proto_msgTypes[17].Exporter = func(v any, i int) any {
+			switch v := v.(*Candle); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_currency_proto_msgTypes[4].OneofWrappers = []any{
+		(*BatchRate_RateResponse)(nil),
+		(*BatchRate_Error)(nil),
 	}
-	file_currency_proto_msgTypes[2].OneofWrappers = []any{
+	file_currency_proto_msgTypes[14].OneofWrappers = []any{
 		(*StreamingRateResponse_RateResponse)(nil),
 		(*StreamingRateResponse_Error)(nil),
 	}
@@ -517,8 +2170,8 @@ func file_currency_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_currency_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   3,
+			NumEnums:      2,
+			NumMessages:   18,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,8 +19,13 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Currency_GetRate_FullMethodName        = "/Currency/GetRate"
-	Currency_SubscribeRates_FullMethodName = "/Currency/SubscribeRates"
+	Currency_GetRate_FullMethodName           = "/Currency/GetRate"
+	Currency_GetRates_FullMethodName          = "/Currency/GetRates"
+	Currency_GetHistoricalRate_FullMethodName = "/Currency/GetHistoricalRate"
+	Currency_Convert_FullMethodName           = "/Currency/Convert"
+	Currency_ListCurrencies_FullMethodName    = "/Currency/ListCurrencies"
+	Currency_SubscribeRates_FullMethodName    = "/Currency/SubscribeRates"
+	Currency_GetCandles_FullMethodName        = "/Currency/GetCandles"
 )
 
 // CurrencyClient is the client API for Currency service.
@@ -29,7 +34,19 @@ const (
 type CurrencyClient interface {
 	// GetRate returns the exchange rate for the two provided currency codes
 	GetRate(ctx context.Context, in *RateRequest, opts ...grpc.CallOption) (*RateResponse, error)
+	// GetRates returns the exchange rates from the base currency to each of the destination currencies
+	GetRates(ctx context.Context, in *BatchRateRequest, opts ...grpc.CallOption) (*BatchRateResponse, error)
+	// GetHistoricalRate returns the exchange rate which applied on the provided date
+	GetHistoricalRate(ctx context.Context, in *HistoricalRateRequest, opts ...grpc.CallOption) (*HistoricalRateResponse, error)
+	// Convert converts an amount of money from the base to the destination currency
+	Convert(ctx context.Context, in *ConvertRequest, opts ...grpc.CallOption) (*ConvertResponse, error)
+	// ListCurrencies returns the details of every currency which has a rate
+	ListCurrencies(ctx context.Context, in *ListCurrenciesRequest, opts ...grpc.CallOption) (*ListCurrenciesResponse, error)
+	// SubscribeRates streams updated rates for the subscribed currency pairs, each
+	// request subscribes to a rate or unsubscribes from it when Unsubscribe is set
 	SubscribeRates(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[RateRequest, StreamingRateResponse], error)
+	// GetCandles returns the open, high, low and close rates for a currency pair over a time range
+	GetCandles(ctx context.Context, in *CandlesRequest, opts ...grpc.CallOption) (*CandlesResponse, error)
 }
 
 type currencyClient struct {
@@ -50,6 +67,46 @@ func (c *currencyClient) GetRate(ctx context.Context, in *RateRequest, opts ...g
 	return out, nil
 }
 
+func (c *currencyClient) GetRates(ctx context.Context, in *BatchRateRequest, opts ...grpc.CallOption) (*BatchRateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchRateResponse)
+	err := c.cc.Invoke(ctx, Currency_GetRates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *currencyClient) GetHistoricalRate(ctx context.Context, in *HistoricalRateRequest, opts ...grpc.CallOption) (*HistoricalRateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HistoricalRateResponse)
+	err := c.cc.Invoke(ctx, Currency_GetHistoricalRate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *currencyClient) Convert(ctx context.Context, in *ConvertRequest, opts ...grpc.CallOption) (*ConvertResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConvertResponse)
+	err := c.cc.Invoke(ctx, Currency_Convert_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *currencyClient) ListCurrencies(ctx context.Context, in *ListCurrenciesRequest, opts ...grpc.CallOption) (*ListCurrenciesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCurrenciesResponse)
+	err := c.cc.Invoke(ctx, Currency_ListCurrencies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *currencyClient) SubscribeRates(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[RateRequest, StreamingRateResponse], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	stream, err := c.cc.NewStream(ctx, &Currency_ServiceDesc.Streams[0], Currency_SubscribeRates_FullMethodName, cOpts...)
@@ -63,13 +120,35 @@ func (c *currencyClient) SubscribeRates(ctx context.Context, opts ...grpc.CallOp
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Currency_SubscribeRatesClient = grpc.BidiStreamingClient[RateRequest, StreamingRateResponse]
 
+func (c *currencyClient) GetCandles(ctx context.Context, in *CandlesRequest, opts ...grpc.CallOption) (*CandlesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CandlesResponse)
+	err := c.cc.Invoke(ctx, Currency_GetCandles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CurrencyServer is the server API for Currency service.
 // All implementations must embed UnimplementedCurrencyServer
 // for forward compatibility.
 type CurrencyServer interface {
 	// GetRate returns the exchange rate for the two provided currency codes
 	GetRate(context.Context, *RateRequest) (*RateResponse, error)
+	// GetRates returns the exchange rates from the base currency to each of the destination currencies
+	GetRates(context.Context, *BatchRateRequest) (*BatchRateResponse, error)
+	// GetHistoricalRate returns the exchange rate which applied on the provided date
+	GetHistoricalRate(context.Context, *HistoricalRateRequest) (*HistoricalRateResponse, error)
+	// Convert converts an amount of money from the base to the destination currency
+	Convert(context.Context, *ConvertRequest) (*ConvertResponse, error)
+	// ListCurrencies returns the details of every currency which has a rate
+	ListCurrencies(context.Context, *ListCurrenciesRequest) (*ListCurrenciesResponse, error)
+	// SubscribeRates streams updated rates for the subscribed currency pairs, each
+	// request subscribes to a rate or unsubscribes from it when Unsubscribe is set
 	SubscribeRates(grpc.BidiStreamingServer[RateRequest, StreamingRateResponse]) error
+	// GetCandles returns the open, high, low and close rates for a currency pair over a time range
+	GetCandles(context.Context, *CandlesRequest) (*CandlesResponse, error)
 	mustEmbedUnimplementedCurrencyServer()
 }
 
@@ -83,9 +162,24 @@ type UnimplementedCurrencyServer struct{}
 func (UnimplementedCurrencyServer) GetRate(context.Context, *RateRequest) (*RateResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetRate not implemented")
 }
+func (UnimplementedCurrencyServer) GetRates(context.Context, *BatchRateRequest) (*BatchRateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRates not implemented")
+}
+func (UnimplementedCurrencyServer) GetHistoricalRate(context.Context, *HistoricalRateRequest) (*HistoricalRateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHistoricalRate not implemented")
+}
+func (UnimplementedCurrencyServer) Convert(context.Context, *ConvertRequest) (*ConvertResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Convert not implemented")
+}
+func (UnimplementedCurrencyServer) ListCurrencies(context.Context, *ListCurrenciesRequest) (*ListCurrenciesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCurrencies not implemented")
+}
 func (UnimplementedCurrencyServer) SubscribeRates(grpc.BidiStreamingServer[RateRequest, StreamingRateResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method SubscribeRates not implemented")
 }
+func (UnimplementedCurrencyServer) GetCandles(context.Context, *CandlesRequest) (*CandlesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCandles not implemented")
+}
 func (UnimplementedCurrencyServer) mustEmbedUnimplementedCurrencyServer() {}
 func (UnimplementedCurrencyServer) testEmbeddedByValue()                  {}
 
@@ -125,6 +219,78 @@ func _Currency_GetRate_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Currency_GetRates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchRateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CurrencyServer).GetRates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Currency_GetRates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CurrencyServer).GetRates(ctx, req.(*BatchRateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Currency_GetHistoricalRate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HistoricalRateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CurrencyServer).GetHistoricalRate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Currency_GetHistoricalRate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CurrencyServer).GetHistoricalRate(ctx, req.(*HistoricalRateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Currency_Convert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConvertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CurrencyServer).Convert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Currency_Convert_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CurrencyServer).Convert(ctx, req.(*ConvertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Currency_ListCurrencies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCurrenciesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CurrencyServer).ListCurrencies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Currency_ListCurrencies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CurrencyServer).ListCurrencies(ctx, req.(*ListCurrenciesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Currency_SubscribeRates_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(CurrencyServer).SubscribeRates(&grpc.GenericServerStream[RateRequest, StreamingRateResponse]{ServerStream: stream})
 }
@@ -132,6 +298,24 @@ func _Currency_SubscribeRates_Handler(srv interface{}, stream grpc.ServerStream)
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Currency_SubscribeRatesServer = grpc.BidiStreamingServer[RateRequest, StreamingRateResponse]
 
+func _Currency_GetCandles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CandlesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CurrencyServer).GetCandles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Currency_GetCandles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CurrencyServer).GetCandles(ctx, req.(*CandlesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Currency_ServiceDesc is the grpc.ServiceDesc for Currency service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -143,6 +327,26 @@ var Currency_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetRate",
 			Handler:    _Currency_GetRate_Handler,
 		},
+		{
+			MethodName: "GetRates",
+			Handler:    _Currency_GetRates_Handler,
+		},
+		{
+			MethodName: "GetHistoricalRate",
+			Handler:    _Currency_GetHistoricalRate_Handler,
+		},
+		{
+			MethodName: "Convert",
+			Handler:    _Currency_Convert_Handler,
+		},
+		{
+			MethodName: "ListCurrencies",
+			Handler:    _Currency_ListCurrencies_Handler,
+		},
+		{
+			MethodName: "GetCandles",
+			Handler:    _Currency_GetCandles_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
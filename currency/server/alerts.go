@@ -0,0 +1,157 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	protos "github.com/hnsia/go-nic/currency/protos/currency/currency"
+)
+
+// alert decides when an update for a subscription with conditions is sent,
+// a nil alert sends every update
+type alert struct {
+	cond *protos.AlertConditions
+	send func(*protos.StreamingRateResponse) // sends a held back update
+
+	// mu guards the fields below
+	mu      sync.Mutex
+	last    float64                       // rate when subscribed or the last update was sent
+	rate    float64                       // rate at the last check
+	sent    time.Time                     // time the last update was sent
+	above   bool                          // rate was above the threshold at the last check
+	below   bool                          // rate was below the threshold at the last check
+	held    *protos.StreamingRateResponse // latest update held back by the minimum interval
+	heldAt  float64                       // rate of the held back update
+	timer   *time.Timer                   // sends the held back update when the interval has passed
+	stopped bool                          // set when the subscription has been removed
+}
+
+// newAlert returns an alert for the conditions, the conditions are evaluated
+// against the rate at the time of subscribing. Updates held back by the minimum
+// interval are passed to send once the interval has passed. A nil alert is
+// returned when there are no conditions
+func newAlert(c *protos.AlertConditions, rate float64, send func(*protos.StreamingRateResponse)) *alert {
+	if c == nil {
+		return nil
+	}
+
+	a := &alert{cond: c, send: send, last: rate, rate: rate}
+	a.above, a.below = a.thresholds(rate)
+
+	return a
+}
+
+// validateConditions returns an error when the conditions can not be used
+func validateConditions(c *protos.AlertConditions) error {
+	switch {
+	case c == nil:
+		return nil
+	case c.GetMinChangePercent() < 0:
+		return fmt.Errorf("MinChangePercent can not be negative")
+	case c.GetAbove() < 0:
+		return fmt.Errorf("Above can not be negative")
+	case c.GetBelow() < 0:
+		return fmt.Errorf("Below can not be negative")
+	case c.GetMinInterval() != nil && (c.GetMinInterval().CheckValid() != nil || c.GetMinInterval().AsDuration() < 0):
+		return fmt.Errorf("MinInterval must be a positive duration")
+	}
+
+	return nil
+}
+
+// check returns true when the update with the rate should be sent. An update
+// which fires inside the minimum interval is held back and sent when the
+// interval has passed, unless a later update is sent first
+func (a *alert) check(rate float64, now time.Time, update *protos.StreamingRateResponse) bool {
+	if a == nil {
+		return true
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.rate = rate
+	above, below := a.thresholds(rate)
+
+	// without any conditions which fire on the rate every change fires
+	fire := a.cond.GetMinChangePercent() == 0 && a.cond.GetAbove() == 0 && a.cond.GetBelow() == 0
+
+	if p := a.cond.GetMinChangePercent(); p > 0 && math.Abs(rate-a.last)/a.last*100 >= p {
+		fire = true
+	}
+
+	// thresholds fire once when they are crossed
+	if above && !a.above || below && !a.below {
+		fire = true
+	}
+
+	// hold back the update without changing the state, the
+	// update is sent once the interval has passed
+	if wait := a.sent.Add(a.cond.GetMinInterval().AsDuration()).Sub(now); fire && wait > 0 {
+		a.held, a.heldAt = update, rate
+		if a.timer == nil && !a.stopped {
+			a.timer = time.AfterFunc(wait, a.flush)
+		}
+
+		return false
+	}
+
+	a.above, a.below = above, below
+	if fire {
+		a.last = rate
+		a.sent = now
+		a.clearHeld()
+	}
+
+	return fire
+}
+
+// flush sends the update which was held back by the minimum interval
+func (a *alert) flush() {
+	a.mu.Lock()
+	held := a.held
+	if held == nil || a.stopped {
+		a.mu.Unlock()
+		return
+	}
+
+	a.above, a.below = a.thresholds(a.rate)
+	a.last = a.heldAt
+	a.sent = time.Now()
+	a.clearHeld()
+	a.mu.Unlock()
+
+	a.send(held)
+}
+
+// stop discards a held back update, it is called when the subscription is removed
+func (a *alert) stop() {
+	if a == nil {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.stopped = true
+	a.clearHeld()
+}
+
+// clearHeld discards the held back update, the caller must hold the lock
+func (a *alert) clearHeld() {
+	a.held = nil
+	if a.timer != nil {
+		a.timer.Stop()
+		a.timer = nil
+	}
+}
+
+// thresholds returns if the rate is above and below the thresholds
+func (a *alert) thresholds(rate float64) (bool, bool) {
+	above := a.cond.GetAbove() > 0 && rate > a.cond.GetAbove()
+	below := a.cond.GetBelow() > 0 && rate < a.cond.GetBelow()
+
+	return above, below
+}
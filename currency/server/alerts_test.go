@@ -0,0 +1,194 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hnsia/go-nic/currency/data"
+	protos "github.com/hnsia/go-nic/currency/protos/currency/currency"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestAlertConditions(t *testing.T) {
+	start := time.Now()
+
+	tests := []struct {
+		name  string
+		cond  *protos.AlertConditions
+		rates []float64 // rates at one second intervals
+		sent  []bool
+	}{
+		{
+			"no conditions",
+			nil,
+			[]float64{1, 1.001, 1.002},
+			[]bool{true, true, true},
+		},
+		{
+			"min change",
+			&protos.AlertConditions{MinChangePercent: 1},
+			[]float64{1.005, 1.01, 1.015, 1.0302, 1.0},
+			[]bool{false, true, false, true, true},
+		},
+		{
+			"above is sent once when crossed",
+			&protos.AlertConditions{Above: 1.1},
+			[]float64{1.05, 1.11, 1.12, 1.09, 1.15},
+			[]bool{false, true, false, false, true},
+		},
+		{
+			"below",
+			&protos.AlertConditions{Below: 0.9},
+			[]float64{0.95, 0.89, 0.85, 0.91},
+			[]bool{false, true, false, false},
+		},
+		{
+			"min interval",
+			&protos.AlertConditions{MinInterval: durationpb.New(2 * time.Second)},
+			[]float64{1.01, 1.02, 1.03, 1.04},
+			[]bool{true, false, true, false},
+		},
+		{
+			"min interval holds back a crossed threshold",
+			&protos.AlertConditions{Above: 1.1, MinInterval: durationpb.New(3 * time.Second)},
+			[]float64{1.11, 1.0, 1.12, 1.13},
+			[]bool{true, false, false, true},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			a := newAlert(tc.cond, 1, func(*protos.StreamingRateResponse) {})
+			defer a.stop()
+
+			for i, r := range tc.rates {
+				sent := a.check(r, start.Add(time.Duration(i)*time.Second), nil)
+				if sent != tc.sent[i] {
+					t.Fatalf("expected sent to be %t for rate %f at step %d", tc.sent[i], r, i)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateConditions(t *testing.T) {
+	invalid := []*protos.AlertConditions{
+		{MinChangePercent: -1},
+		{Above: -1},
+		{Below: -1},
+		{MinInterval: durationpb.New(-time.Second)},
+	}
+
+	for _, c := range invalid {
+		if validateConditions(c) == nil {
+			t.Fatalf("expected conditions %v to be invalid", c)
+		}
+	}
+}
+
+func TestSubscribeRatesOnlySendsAlerts(t *testing.T) {
+	r, err := data.NewRates(hclog.NewNullLogger(), data.NewMemory(data.SampleRates))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// broadcasts are triggered by the test
+	c := &Currency{rates: r, log: hclog.NewNullLogger(), subscriptions: newSubscriptions(Queue{}, nil, hclog.NewNullLogger())}
+	s := newTestStream()
+
+	done := make(chan error)
+	go func() { done <- c.SubscribeRates(s) }()
+
+	s.send(&protos.RateRequest{
+		BaseCode:        "EUR",
+		DestinationCode: "USD",
+		Conditions:      &protos.AlertConditions{MinChangePercent: 50},
+	})
+
+	// a subscription without conditions receives every update
+	s.send(&protos.RateRequest{BaseCode: "EUR", DestinationCode: "GBP"})
+
+	s.send(&protos.RateRequest{
+		BaseCode:        "EUR",
+		DestinationCode: "JPY",
+		Conditions:      &protos.AlertConditions{Above: -1},
+	})
+
+	sim := data.NewSimulator(data.Simulation{Seed: 1, MaxDrift: 0.01})
+	for i := 0; i < 5; i++ {
+		r.Tick(sim)
+		c.broadcast()
+	}
+
+	close(s.recv)
+	<-done
+
+	var gbp, invalid int
+	for _, m := range s.messages() {
+		switch {
+		case m.GetRateResponse().GetDestinationCode() == "USD":
+			t.Fatalf("expected no updates for USD, got %v", m)
+		case m.GetRateResponse().GetDestinationCode() == "GBP":
+			gbp++
+		case codes.Code(m.GetError().GetCode()) == codes.InvalidArgument:
+			invalid++
+		}
+	}
+
+	if gbp != 5 || invalid != 1 {
+		t.Fatalf("expected 5 GBP updates and 1 invalid argument error, got %d and %d", gbp, invalid)
+	}
+}
+
+func TestSubscribeRatesSendsHeldBackAlert(t *testing.T) {
+	m := data.NewMemory(map[string]float64{"USD": 1})
+	r, err := data.NewRates(hclog.NewNullLogger(), m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// broadcasts are triggered by the test
+	c := &Currency{rates: r, log: hclog.NewNullLogger(), subscriptions: newSubscriptions(Queue{}, nil, hclog.NewNullLogger())}
+	s := newTestStream()
+
+	done := make(chan error)
+	go func() { done <- c.SubscribeRates(s) }()
+
+	s.send(&protos.RateRequest{
+		BaseCode:        "EUR",
+		DestinationCode: "USD",
+		Conditions:      &protos.AlertConditions{Above: 1.1, MinInterval: durationpb.New(200 * time.Millisecond)},
+	})
+
+	update := func(rate float64) {
+		m.Set(map[string]float64{"USD": rate})
+		_, err := r.Refresh(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		c.broadcast()
+	}
+
+	// the threshold is crossed, falls back and is crossed again inside the interval
+	update(1.2)
+	s.wait(t, 1)
+	update(1.0)
+	update(1.3)
+
+	if n := len(s.messages()); n != 1 {
+		t.Fatalf("expected the second crossing to be held back, got %d messages", n)
+	}
+
+	// no further update arrives, the held back rate is sent when the interval has passed
+	msgs := s.wait(t, 2)
+	if rate := msgs[1].GetRateResponse().GetRate(); rate != 1.3 {
+		t.Fatalf("expected the held back rate 1.3, got %f", rate)
+	}
+
+	close(s.recv)
+	<-done
+}
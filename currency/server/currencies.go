@@ -0,0 +1,95 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/hnsia/go-nic/currency/data"
+	protos "github.com/hnsia/go-nic/currency/protos/currency/currency"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// currencyCode returns the string code when it is set, otherwise the code of
+// the enum value. Older clients only set the enum
+func currencyCode(code string, c protos.Currencies) string {
+	if code != "" {
+		return strings.ToUpper(code)
+	}
+
+	return c.String()
+}
+
+// rateCodes returns the base and destination currency codes for the request
+func rateCodes(rr *protos.RateRequest) (string, string) {
+	return currencyCode(rr.GetBaseCode(), rr.GetBase()), currencyCode(rr.GetDestinationCode(), rr.GetDestination())
+}
+
+// enumCurrency returns the enum value for the code, currencies which
+// are not part of the enum return the zero value
+func enumCurrency(code string) protos.Currencies {
+	return protos.Currencies(protos.Currencies_value[code])
+}
+
+// newRateResponse returns a RateResponse for the quote with both the enum and string codes set
+func newRateResponse(base, dest string, q data.Quote) *protos.RateResponse {
+	return &protos.RateResponse{
+		Base:            enumCurrency(base),
+		Destination:     enumCurrency(dest),
+		Rate:            q.Rate,
+		BaseCode:        base,
+		DestinationCode: dest,
+		AsOf:            timestamppb.New(q.AsOf),
+		Source:          q.Source,
+		Stale:           q.Stale,
+		Bid:             q.Bid,
+		Ask:             q.Ask,
+		Profile:         q.Profile,
+	}
+}
+
+// validateRate returns an InvalidArgument error when the base and destination
+// are the same or either currency does not have a rate, the request is
+// attached to the error as metadata
+func (c *Currency) validateRate(base, dest string, req protoadapt.MessageV1) *status.Status {
+	var err *status.Status
+
+	switch {
+	case base == dest:
+		err = status.Newf(
+			codes.InvalidArgument,
+			"Base currency %s can not be the same as the destination currency %s",
+			base,
+			dest,
+		)
+	case !c.rates.Supported(base):
+		err = status.Newf(codes.InvalidArgument, "Currency %s is not supported", base)
+	case !c.rates.Supported(dest):
+		err = status.Newf(codes.InvalidArgument, "Currency %s is not supported", dest)
+	default:
+		return nil
+	}
+
+	return withDetails(err, req)
+}
+
+// validateProfile returns an InvalidArgument error when the spread profile
+// does not exist, the request is attached to the error as metadata
+func (c *Currency) validateProfile(profile string, req protoadapt.MessageV1) *status.Status {
+	if c.rates.HasProfile(profile) {
+		return nil
+	}
+
+	return withDetails(status.Newf(codes.InvalidArgument, "Spread profile %s does not exist", profile), req)
+}
+
+// withDetails attaches the request to the error as metadata
+func withDetails(err *status.Status, req protoadapt.MessageV1) *status.Status {
+	wd, wde := err.WithDetails(req)
+	if wde != nil {
+		return status.New(codes.Internal, wde.Error())
+	}
+
+	return wd
+}
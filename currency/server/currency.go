@@ -2,73 +2,223 @@ package server
 
 import (
 	"context"
+	"errors"
 	"io"
+	"runtime/debug"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/hnsia/go-nic/currency/data"
+	"github.com/hnsia/go-nic/currency/metrics"
 	protos "github.com/hnsia/go-nic/currency/protos/currency/currency"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// drainMessage is the status message sent to streams when the server shuts down
+const drainMessage = "Server is shutting down, reconnect to continue receiving rates"
+
+// overflowMessage is the status returned to streams disconnected for reading messages too slowly
+const overflowMessage = "Client is not reading rates fast enough, reconnect to continue receiving rates"
+
 type Currency struct {
 	rates         *data.ExchangeRates
 	log           hclog.Logger
-	subscriptions map[protos.Currency_SubscribeRatesServer][]*protos.RateRequest
+	metrics       *metrics.Metrics
+	subscriptions *subscriptions
+	drain         chan struct{} // closed when the server starts to shut down
+	drainOnce     sync.Once
 	protos.UnimplementedCurrencyServer
 }
 
-func NewCurrency(r *data.ExchangeRates, l hclog.Logger) *Currency {
-	subscriptions := make(map[protos.Currency_SubscribeRatesServer][]*protos.RateRequest)
+// NewCurrency creates a new currency server, q configures the queue of messages
+// for each subscriber and m may be nil when metrics are not required
+func NewCurrency(r *data.ExchangeRates, q Queue, m *metrics.Metrics, l hclog.Logger) *Currency {
+	c := &Currency{
+		rates:         r,
+		log:           l,
+		metrics:       m,
+		subscriptions: newSubscriptions(q, m, l),
+		drain:         make(chan struct{}),
+	}
 
-	c := &Currency{r, l, subscriptions, protos.UnimplementedCurrencyServer{}}
 	go c.handleUpdates()
 
 	return c
 }
 
+// Drain ends every SubscribeRates stream with an Unavailable status which tells
+// the client to reconnect, new streams are rejected. Drain is called before the
+// gRPC server is stopped so clients are not cut off in the middle of a message
+func (c *Currency) Drain() {
+	c.drainOnce.Do(func() {
+		c.log.Info("Draining rate subscriptions", "subscribers", len(c.subscriptions.Subscribers()))
+		close(c.drain)
+	})
+}
+
+// draining returns true once Drain has been called
+func (c *Currency) draining() bool {
+	select {
+	case <-c.drain:
+		return true
+	default:
+		return false
+	}
+}
+
 func (c *Currency) handleUpdates() {
-	ru := c.rates.MonitorRates(5 * time.Second)
-	for range ru {
+	for range c.rates.Updates() {
 		c.log.Info("Got updated rates")
+		c.broadcast()
+	}
+}
+
+// Replay replays the recording to the subscribed clients. The replay starts when
+// the first client subscribes to a rate so the start of the recording is not
+// lost, it does not wait when the context is cancelled first. Each record is
+// queued for the clients before the next record is applied so they receive the
+// recorded sequence of rates at any replay speed, subject to the overflow policy
+// of their queues. Alert conditions are evaluated at the recorded time of each record
+func (c *Currency) Replay(ctx context.Context, r *data.Replay) {
+	go func() {
+		c.log.Info("Waiting for a subscription before replaying")
+
+		select {
+		case <-c.subscriptions.subscribed:
+		case <-ctx.Done():
+			return
+		}
+
+		c.rates.ReplayRates(ctx, r, func(rec data.Record) {
+			c.broadcastAt(rec.Time)
+		})
+	}()
+}
+
+// broadcast queues the current rates for every subscribed client, it does
+// not wait for the clients to receive them
+func (c *Currency) broadcast() {
+	c.broadcastAt(time.Now())
+}
+
+// broadcastAt queues the current rates for every subscribed client, alert
+// conditions are evaluated at the given time
+func (c *Currency) broadcastAt(now time.Time) {
+	c.metrics.Broadcast()
 
-		// loop over subscribed clients
-		for k, v := range c.subscriptions {
-
-			// loop over subscribed rates
-			for _, rr := range v {
-				r, err := c.rates.GetRate(rr.GetBase().String(), rr.GetDestination().String())
-				if err != nil {
-					c.log.Error("Unable to get updated rate", "base", rr.GetBase().String(), "destination", rr.GetDestination().String())
-				}
-
-				err = k.Send(&protos.StreamingRateResponse{
-					Message: &protos.StreamingRateResponse_RateResponse{
-						RateResponse: &protos.RateResponse{Base: rr.Base, Destination: rr.Destination, Rate: r},
-					},
-				})
-				if err != nil {
-					c.log.Error("Unable to send updated rate", "base", rr.GetBase().String(), "destination", rr.GetDestination().String())
-				}
+	// loop over subscribed clients
+	for _, sub := range c.subscriptions.Subscribers() {
+
+		// loop over subscribed rates
+		for _, s := range sub.Requests() {
+			rr := s.request
+			base, dest := rateCodes(rr)
+
+			q, err := c.rates.GetQuote(base, dest, rr.GetProfile())
+			if err != nil {
+				c.log.Error("Unable to get updated rate", "base", base, "destination", dest)
+				continue
+			}
+
+			update := &protos.StreamingRateResponse{
+				Message: &protos.StreamingRateResponse_RateResponse{
+					RateResponse: newRateResponse(base, dest, q),
+				},
 			}
+
+			// only send the update when the conditions of the subscription fire
+			if !s.alert.check(q.Rate, now, update) {
+				continue
+			}
+
+			sub.Send(update)
 		}
 	}
 }
 
 func (c *Currency) GetRate(ctx context.Context, rr *protos.RateRequest) (*protos.RateResponse, error) {
-	c.log.Info("Handle GetRate", "base", rr.GetBase(), "destination", rr.GetDestination())
+	base, dest := rateCodes(rr)
+	c.log.Info("Handle GetRate", "base", base, "destination", dest)
+
+	if err := c.validateRate(base, dest, rr); err != nil {
+		return nil, err.Err()
+	}
+
+	if err := c.validateProfile(rr.GetProfile(), rr); err != nil {
+		return nil, err.Err()
+	}
+
+	q, err := c.rates.GetQuote(base, dest, rr.GetProfile())
+	if err != nil {
+		return nil, err
+	}
 
-	if rr.Base == rr.Destination {
+	return newRateResponse(base, dest, q), nil
+}
+
+// GetRates implements the gRPC unary method returning many rates for a single base currency
+func (c *Currency) GetRates(ctx context.Context, br *protos.BatchRateRequest) (*protos.BatchRateResponse, error) {
+	base := currencyCode(br.GetBaseCode(), br.GetBase())
+
+	dests := []string{}
+	for _, d := range br.GetDestinations() {
+		dests = append(dests, d.String())
+	}
+	for _, d := range br.GetDestinationCodes() {
+		dests = append(dests, currencyCode(d, 0))
+	}
+
+	c.log.Info("Handle GetRates", "base", base, "destinations", len(dests))
+
+	rates, errs := c.rates.GetRates(base, dests)
+
+	resp := &protos.BatchRateResponse{Rates: make([]*protos.BatchRate, len(dests))}
+	for i, d := range dests {
+		// add the request for the failed rate as metadata
+		rr := &protos.RateRequest{Base: enumCurrency(base), Destination: enumCurrency(d), BaseCode: base, DestinationCode: d}
+
+		err := c.validateRate(base, d, rr)
+		if err == nil && errs[i] != nil {
+			err = status.New(codes.NotFound, errs[i].Error())
+		}
+
+		if err != nil {
+			resp.Rates[i] = &protos.BatchRate{Message: &protos.BatchRate_Error{Error: err.Proto()}}
+			continue
+		}
+
+		resp.Rates[i] = &protos.BatchRate{
+			Message: &protos.BatchRate_RateResponse{
+				RateResponse: newRateResponse(base, d, rates[i]),
+			},
+		}
+	}
+
+	return resp, nil
+}
+
+// GetHistoricalRate implements the gRPC unary method returning the rate which applied on a past date
+func (c *Currency) GetHistoricalRate(ctx context.Context, hr *protos.HistoricalRateRequest) (*protos.HistoricalRateResponse, error) {
+	base, dest := rateCodes(hr.GetRequest())
+	c.log.Info("Handle GetHistoricalRate", "base", base, "destination", dest, "date", hr.GetDate().AsTime())
+
+	// currencies are not validated against the current rates as
+	// historical rates can contain currencies which are no longer published
+	if base == dest {
 		err := status.Newf(
 			codes.InvalidArgument,
 			"Base currency %s can not be the same as the destination currency %s",
-			rr.Base.String(),
-			rr.Destination.String(),
+			base,
+			dest,
 		)
 
-		err, wde := err.WithDetails(rr)
+		err, wde := err.WithDetails(hr)
 		if wde != nil {
 			return nil, wde
 		}
@@ -76,78 +226,265 @@ func (c *Currency) GetRate(ctx context.Context, rr *protos.RateRequest) (*protos
 		return nil, err.Err()
 	}
 
-	rate, err := c.rates.GetRate(rr.GetBase().String(), rr.GetDestination().String())
+	if hr.GetDate() == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Date is required")
+	}
+
+	q, err := c.rates.GetHistoricalRate(base, dest, hr.GetDate().AsTime())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	return &protos.HistoricalRateResponse{
+		Response: newRateResponse(base, dest, q),
+		Date:     timestamppb.New(q.AsOf),
+	}, nil
+}
+
+// Convert implements the gRPC unary method converting an amount of money between currencies
+func (c *Currency) Convert(ctx context.Context, cr *protos.ConvertRequest) (*protos.ConvertResponse, error) {
+	base := currencyCode(cr.GetBaseCode(), cr.GetBase())
+	dest := currencyCode(cr.GetDestinationCode(), cr.GetDestination())
+	c.log.Info("Handle Convert", "base", base, "destination", dest)
+
+	if err := c.validateRate(base, dest, cr); err != nil {
+		return nil, err.Err()
+	}
+
+	if err := c.validateProfile(cr.GetProfile(), cr); err != nil {
+		return nil, err.Err()
+	}
+
+	amount, err := moneyToRat(cr.GetAmount())
+	if err != nil {
+		err := status.Newf(codes.InvalidArgument, "Invalid amount: %s", err)
+
+		err, wde := err.WithDetails(cr)
+		if wde != nil {
+			return nil, wde
+		}
+
+		return nil, err.Err()
+	}
+
+	side := data.Sell
+	if cr.GetSide() == protos.Side_BUY {
+		side = data.Buy
+	}
+
+	converted, rate, err := c.rates.Convert(base, dest, cr.GetProfile(), side, amount)
 	if err != nil {
 		return nil, err
 	}
 
-	return &protos.RateResponse{Base: rr.Base, Destination: rr.Destination, Rate: rate}, nil
+	m, err := ratToMoney(converted)
+	if err != nil {
+		return nil, status.Error(codes.OutOfRange, err.Error())
+	}
+
+	return &protos.ConvertResponse{
+		Amount:          m,
+		Base:            enumCurrency(base),
+		Destination:     enumCurrency(dest),
+		Rate:            rate,
+		BaseCode:        base,
+		DestinationCode: dest,
+	}, nil
+}
+
+// ListCurrencies implements the gRPC unary method returning the supported currencies
+func (c *Currency) ListCurrencies(ctx context.Context, lr *protos.ListCurrenciesRequest) (*protos.ListCurrenciesResponse, error) {
+	c.log.Info("Handle ListCurrencies")
+
+	resp := &protos.ListCurrenciesResponse{}
+	for _, ci := range c.rates.Currencies() {
+		resp.Currencies = append(resp.Currencies, &protos.CurrencyInfo{
+			Code:        ci.Code,
+			Name:        ci.Name,
+			NumericCode: ci.NumericCode,
+			MinorUnits:  int32(ci.MinorUnits),
+			Symbol:      ci.Symbol,
+		})
+	}
+
+	return resp, nil
+}
+
+// GetCandles implements the gRPC unary method returning the candles for a currency pair
+func (c *Currency) GetCandles(ctx context.Context, cr *protos.CandlesRequest) (*protos.CandlesResponse, error) {
+	c.log.Info("Handle GetCandles", "pair", cr.GetPair(), "resolution", cr.GetResolution())
+
+	res, err := data.ParseResolution(cr.GetResolution())
+	if err != nil {
+		return nil, withDetails(status.New(codes.InvalidArgument, err.Error()), cr).Err()
+	}
+
+	to := time.Now()
+	if cr.GetTo() != nil {
+		to = cr.GetTo().AsTime()
+	}
+
+	from := cr.GetFrom().AsTime()
+	if from.After(to) {
+		return nil, withDetails(status.New(codes.InvalidArgument, "From can not be after To"), cr).Err()
+	}
+
+	cs := c.rates.Candles()
+	if cs == nil {
+		return nil, status.Error(codes.NotFound, "Candles are not recorded")
+	}
+
+	candles, err := cs.Get(cr.GetPair(), res, from, to)
+	switch {
+	case errors.Is(err, data.ErrPairNotTracked), errors.Is(err, data.ErrResolutionNotTracked):
+		return nil, withDetails(status.New(codes.NotFound, err.Error()), cr).Err()
+	case err != nil:
+		return nil, withDetails(status.New(codes.InvalidArgument, err.Error()), cr).Err()
+	}
+
+	resp := &protos.CandlesResponse{Pair: strings.ToUpper(cr.GetPair()), Resolution: res.String()}
+	for _, cd := range candles {
+		resp.Candles = append(resp.Candles, &protos.Candle{
+			Start: timestamppb.New(cd.Start),
+			Open:  cd.Open,
+			High:  cd.High,
+			Low:   cd.Low,
+			Close: cd.Close,
+		})
+	}
+
+	return resp, nil
 }
 
 // SubscribeRates implements the gRPC bidirectional streaming method for the server
 func (c *Currency) SubscribeRates(src grpc.BidiStreamingServer[protos.RateRequest, protos.StreamingRateResponse]) error {
+	if c.draining() {
+		return status.Error(codes.Unavailable, drainMessage)
+	}
+
+	sub := c.subscriptions.Get(src)
+
+	// remove all subscriptions for the client when the stream ends
+	defer c.subscriptions.Remove(src)
+
+	// requests are handled in a separate goroutine so the stream
+	// can be ended when the server drains while the client is idle
+	done := make(chan error, 1)
+	go func() {
+		// the goroutine is not covered by the recovery interceptor, a panic
+		// ends the stream with an Internal error instead of the process
+		defer func() {
+			if r := recover(); r != nil {
+				method := protos.Currency_SubscribeRates_FullMethodName
+				c.log.Error("Recovered from panic", "method", method, "panic", r, "stack", string(debug.Stack()))
+				done <- status.Errorf(codes.Internal, "Internal error handling %s", method)
+			}
+		}()
+
+		done <- c.handleRequests(src, sub)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-c.drain:
+		s := status.New(codes.Unavailable, drainMessage)
+		c.sendStatus(sub, s)
+
+		return s.Err()
+	case <-sub.queue.overflowed():
+		// the queue is full so the status is returned rather than queued
+		c.log.Warn("Disconnecting slow client", "policy", Disconnect)
+
+		return status.Error(codes.ResourceExhausted, overflowMessage)
+	}
+}
 
+// handleRequests handles the subscribe and unsubscribe requests from the client until the stream ends
+func (c *Currency) handleRequests(src protos.Currency_SubscribeRatesServer, sub *subscriber) error {
 	// handle client messages
 	for {
-		rr, err := src.Recv() // Recv is a blocking method which returns on client data
+		req, err := src.Recv() // Recv is a blocking method which returns on client data
 		// io.EOF signals that the client has closed the connection
 		if err == io.EOF {
 			c.log.Info("Client has closed connection")
 			break
 		}
 
-		// any other error means the transport between the server and client is unavailable
+		// any other error means the transport between the server and client is unavailable,
+		// this includes the client cancelling the context for the stream
 		if err != nil {
 			c.log.Error("Unable to read from client", "error", err)
 			return err
 		}
 
-		c.log.Info("Handle client request", "request", rr)
-		rrs, ok := c.subscriptions[src]
-		if !ok {
-			rrs = []*protos.RateRequest{}
-		}
+		c.log.Info("Handle client request", "request", req)
 
-		// check that subscription does not exists
-		var validationError *status.Status
-		for _, v := range rrs {
-			if v.Base == rr.Base && v.Destination == rr.Destination {
-				// subscription exists return errors
-				validationError := status.Newf(
-					codes.AlreadyExists,
-					"Unable to subscribe for currency as subscription already exists",
-				)
-
-				// add the original request as metadata
-				validationError, err = validationError.WithDetails(rr)
-				if err != nil {
-					c.log.Error("Unable to add metadata to error", "error", err)
-					break
-				}
-
-				break
+		if req.GetUnsubscribe() {
+			if !sub.Remove(req) {
+				c.sendError(sub, codes.NotFound, "Unable to unsubscribe for currency as subscription does not exist", req)
 			}
+
+			continue
 		}
 
-		// if validation error return, error and continue
-		if validationError != nil {
-			src.Send(
-				&protos.StreamingRateResponse{
-					Message: &protos.StreamingRateResponse_Error{
-						Error: validationError.Proto(),
-					},
-				},
-			)
+		base, dest := rateCodes(req)
+		if err := c.validateRate(base, dest, req); err != nil {
+			c.sendStatus(sub, err)
 			continue
 		}
 
-		rrs = append(rrs, rr)
-		c.subscriptions[src] = rrs
+		if err := c.validateProfile(req.GetProfile(), req); err != nil {
+			c.sendStatus(sub, err)
+			continue
+		}
+
+		if err := validateConditions(req.GetConditions()); err != nil {
+			c.sendError(sub, codes.InvalidArgument, "Invalid alert conditions: "+err.Error(), req)
+			continue
+		}
+
+		// conditions are evaluated against the rate at the time of subscribing
+		rate, _ := c.rates.GetRate(base, dest)
+
+		// check that subscription does not exists
+		if !sub.Add(req, newAlert(req.GetConditions(), rate, sub.Send)) {
+			// subscription exists return errors
+			c.sendError(sub, codes.AlreadyExists, "Unable to subscribe for currency as subscription already exists", req)
+			continue
+		}
+
+		c.subscriptions.added()
 	}
 
 	return nil
 }
 
+// sendError sends an error to the client with the original request as metadata
+func (c *Currency) sendError(sub *subscriber, code codes.Code, msg string, req protoadapt.MessageV1) {
+	validationError := status.New(code, msg)
+
+	// add the original request as metadata
+	validationError, err := validationError.WithDetails(req)
+	if err != nil {
+		c.log.Error("Unable to add metadata to error", "error", err)
+		return
+	}
+
+	c.sendStatus(sub, validationError)
+}
+
+// sendStatus queues the status for the client as an error message
+func (c *Currency) sendStatus(sub *subscriber, s *status.Status) {
+	sub.Send(
+		&protos.StreamingRateResponse{
+			Message: &protos.StreamingRateResponse_Error{
+				Error: s.Proto(),
+			},
+		},
+	)
+}
+
 // {
 // 	"Base": "JPY",
 // 	"Destination": "USD"
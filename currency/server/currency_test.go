@@ -0,0 +1,686 @@
+package server
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hnsia/go-nic/currency/data"
+	protos "github.com/hnsia/go-nic/currency/protos/currency/currency"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// testStream is a fake SubscribeRates stream, requests are read from
+// the recv channel and sent messages are recorded
+type testStream struct {
+	grpc.ServerStream
+	ctx       context.Context
+	recv      chan *protos.RateRequest
+	next      chan struct{} // signalled when the server is ready for the next request
+	delivered bool
+
+	mu   sync.Mutex
+	sent []*protos.StreamingRateResponse
+}
+
+func newTestStream() *testStream {
+	return &testStream{
+		ctx:  context.Background(),
+		recv: make(chan *protos.RateRequest),
+		next: make(chan struct{}),
+	}
+}
+
+func (t *testStream) Context() context.Context {
+	return t.ctx
+}
+
+func (t *testStream) Recv() (*protos.RateRequest, error) {
+	// the previous request has been handled
+	if t.delivered {
+		t.next <- struct{}{}
+	}
+
+	t.delivered = true
+
+	select {
+	case rr, ok := <-t.recv:
+		if !ok {
+			return nil, io.EOF
+		}
+
+		return rr, nil
+	case <-t.ctx.Done():
+		return nil, t.ctx.Err()
+	}
+}
+
+// send sends the request to the server and waits for it to be handled
+func (t *testStream) send(r *protos.RateRequest) {
+	t.recv <- r
+	<-t.next
+}
+
+func (t *testStream) Send(m *protos.StreamingRateResponse) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.sent = append(t.sent, m)
+
+	return nil
+}
+
+func (t *testStream) messages() []*protos.StreamingRateResponse {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]*protos.StreamingRateResponse{}, t.sent...)
+}
+
+// wait returns the sent messages once at least n have been sent, messages
+// are sent asynchronously from the subscriber queue
+func (t *testStream) wait(tb testing.TB, n int) []*protos.StreamingRateResponse {
+	tb.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		m := t.messages()
+		if len(m) >= n {
+			return m
+		}
+
+		if time.Now().After(deadline) {
+			tb.Fatalf("expected at least %d messages, got %d", n, len(m))
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func subscribe(base, dest protos.Currencies) *protos.RateRequest {
+	return &protos.RateRequest{Base: base, Destination: dest}
+}
+
+func unsubscribe(base, dest protos.Currencies) *protos.RateRequest {
+	return &protos.RateRequest{Base: base, Destination: dest, Unsubscribe: true}
+}
+
+func newTestCurrency(t *testing.T) *Currency {
+	r, err := data.NewRates(hclog.NewNullLogger(), data.NewMemory(data.SampleRates))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewCurrency(r, Queue{}, nil, hclog.NewNullLogger())
+}
+
+func TestSubscribeRatesRejectsDuplicate(t *testing.T) {
+	c := newTestCurrency(t)
+	s := newTestStream()
+
+	done := make(chan error)
+	go func() { done <- c.SubscribeRates(s) }()
+
+	rr := subscribe(protos.Currencies_EUR, protos.Currencies_USD)
+	s.send(rr)
+	s.send(rr)
+	close(s.recv)
+
+	err := <-done
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := s.messages()
+	if len(m) != 1 || m[0].GetError() == nil {
+		t.Fatalf("expected a single error message, got %v", m)
+	}
+}
+
+func TestConcurrentSubscribeAndBroadcast(t *testing.T) {
+	c := newTestCurrency(t)
+	currencies := []protos.Currencies{protos.Currencies_USD, protos.Currencies_GBP, protos.Currencies_JPY}
+
+	wg := sync.WaitGroup{}
+	streams := []*testStream{}
+	for i := 0; i < 10; i++ {
+		s := newTestStream()
+		streams = append(streams, s)
+
+		go c.SubscribeRates(s)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, d := range currencies {
+				s.send(subscribe(protos.Currencies_EUR, d))
+			}
+		}()
+	}
+
+	// broadcast and query rates while clients are subscribing
+	bwg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		bwg.Add(2)
+		go func() {
+			defer bwg.Done()
+			c.broadcast()
+		}()
+
+		go func() {
+			defer bwg.Done()
+			_, err := c.GetRate(context.Background(), &protos.RateRequest{Base: protos.Currencies_GBP, Destination: protos.Currencies_USD})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	wg.Wait()
+	bwg.Wait()
+
+	// every client is now subscribed to all currencies
+	c.broadcast()
+
+	for _, s := range streams {
+		s.wait(t, len(currencies))
+		close(s.recv)
+	}
+}
+
+func TestUnsubscribeStopsUpdates(t *testing.T) {
+	c := newTestCurrency(t)
+	s := newTestStream()
+
+	done := make(chan error)
+	go func() { done <- c.SubscribeRates(s) }()
+
+	s.send(subscribe(protos.Currencies_EUR, protos.Currencies_USD))
+	s.send(subscribe(protos.Currencies_EUR, protos.Currencies_GBP))
+	s.send(unsubscribe(protos.Currencies_EUR, protos.Currencies_USD))
+
+	// unsubscribing twice returns an error
+	s.send(unsubscribe(protos.Currencies_EUR, protos.Currencies_USD))
+
+	c.broadcast()
+
+	m := s.wait(t, 2)
+	if len(m) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(m))
+	}
+
+	if m[0].GetError() == nil {
+		t.Fatalf("expected error for unknown subscription, got %v", m[0])
+	}
+
+	if d := m[1].GetRateResponse().GetDestination(); d != protos.Currencies_GBP {
+		t.Fatalf("expected update for GBP, got %s", d)
+	}
+
+	close(s.recv)
+	<-done
+}
+
+func TestStreamEndRemovesSubscriptions(t *testing.T) {
+	c := newTestCurrency(t)
+
+	closed := newTestStream()
+	cancelled := newTestStream()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled.ctx = ctx
+
+	done := make(chan error, 2)
+	go func() { done <- c.SubscribeRates(closed) }()
+	go func() { done <- c.SubscribeRates(cancelled) }()
+
+	closed.send(subscribe(protos.Currencies_EUR, protos.Currencies_USD))
+	cancelled.send(subscribe(protos.Currencies_EUR, protos.Currencies_USD))
+
+	close(closed.recv)
+	cancel()
+	<-done
+	<-done
+
+	if n := len(c.subscriptions.Subscribers()); n != 0 {
+		t.Fatalf("expected all subscribers to be removed, got %d", n)
+	}
+
+	c.broadcast()
+
+	if n := len(closed.messages()) + len(cancelled.messages()); n != 0 {
+		t.Fatalf("expected no messages after stream ended, got %d", n)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	c := newTestCurrency(t)
+
+	resp, err := c.Convert(context.Background(), &protos.ConvertRequest{
+		Amount:      &protos.Money{Units: -2, Nanos: -450000000},
+		Base:        protos.Currencies_EUR,
+		Destination: protos.Currencies_USD,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// -2.45 * 1.0895 = -2.669275
+	if resp.GetAmount().GetUnits() != -2 || resp.GetAmount().GetNanos() != -670000000 {
+		t.Fatalf("expected -2.67, got %v", resp.GetAmount())
+	}
+
+	_, err = c.Convert(context.Background(), &protos.ConvertRequest{
+		Amount:      &protos.Money{Units: 2, Nanos: -450000000},
+		Base:        protos.Currencies_EUR,
+		Destination: protos.Currencies_USD,
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument for mixed signs, got %v", err)
+	}
+
+	// a missing amount is not converted as zero
+	_, err = c.Convert(context.Background(), &protos.ConvertRequest{
+		Base:        protos.Currencies_EUR,
+		Destination: protos.Currencies_USD,
+	})
+	if st := status.Convert(err); st.Code() != codes.InvalidArgument || len(st.Details()) != 1 {
+		t.Fatalf("expected invalid argument with the request for a missing amount, got %v", err)
+	}
+}
+
+func TestSpreadProfiles(t *testing.T) {
+	profiles := map[string]data.Profile{"retail": {Default: data.Spread{Percent: 1}}}
+
+	r, err := data.NewRates(hclog.NewNullLogger(), data.NewMemory(data.SampleRates), data.WithProfiles(profiles))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewCurrency(r, Queue{}, nil, hclog.NewNullLogger())
+
+	resp, err := c.GetRate(context.Background(), &protos.RateRequest{BaseCode: "EUR", DestinationCode: "USD", Profile: "retail"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.GetRate() != data.SampleRates["USD"] || resp.GetBid() >= resp.GetRate() || resp.GetAsk() <= resp.GetRate() || resp.GetProfile() != "retail" {
+		t.Fatalf("expected bid and ask either side of the mid rate, got %v", resp)
+	}
+
+	// 100 * 1.0895 * 0.99 = 107.8605
+	cr, err := c.Convert(context.Background(), &protos.ConvertRequest{
+		Amount:          &protos.Money{Units: 100},
+		BaseCode:        "EUR",
+		DestinationCode: "USD",
+		Profile:         "retail",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cr.GetAmount().GetUnits() != 107 || cr.GetAmount().GetNanos() != 860000000 {
+		t.Fatalf("expected conversion at the bid rate, got %v", cr.GetAmount())
+	}
+
+	// 100 * 1.0895 * 1.01 = 110.0395
+	cr, err = c.Convert(context.Background(), &protos.ConvertRequest{
+		Amount:          &protos.Money{Units: 100},
+		BaseCode:        "EUR",
+		DestinationCode: "USD",
+		Profile:         "retail",
+		Side:            protos.Side_BUY,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cr.GetAmount().GetUnits() != 110 || cr.GetAmount().GetNanos() != 40000000 {
+		t.Fatalf("expected conversion at the ask rate, got %v", cr.GetAmount())
+	}
+
+	_, err = c.GetRate(context.Background(), &protos.RateRequest{BaseCode: "EUR", DestinationCode: "USD", Profile: "unknown"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument for unknown profile, got %v", err)
+	}
+}
+
+func TestGetRatesReturnsErrorPerDestination(t *testing.T) {
+	c := newTestCurrency(t)
+
+	resp, err := c.GetRates(context.Background(), &protos.BatchRateRequest{
+		Base: protos.Currencies_GBP,
+		Destinations: []protos.Currencies{
+			protos.Currencies_USD,
+			protos.Currencies_GBP,
+			protos.Currencies_HRK, // no longer published
+			protos.Currencies_JPY,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []codes.Code{codes.OK, codes.InvalidArgument, codes.InvalidArgument, codes.OK}
+	if len(resp.GetRates()) != len(expected) {
+		t.Fatalf("expected %d rates, got %d", len(expected), len(resp.GetRates()))
+	}
+
+	for i, r := range resp.GetRates() {
+		code := codes.Code(r.GetError().GetCode())
+		if code != expected[i] {
+			t.Fatalf("expected rate %d to have code %s, got %s", i, expected[i], code)
+		}
+
+		if code == codes.OK && r.GetRateResponse().GetRate() <= 0 {
+			t.Fatalf("expected a rate for destination %d", i)
+		}
+	}
+}
+
+func TestListCurrencies(t *testing.T) {
+	c := newTestCurrency(t)
+
+	resp, err := c.ListCurrencies(context.Background(), &protos.ListCurrenciesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the sample rates plus EUR
+	if len(resp.GetCurrencies()) != len(data.SampleRates)+1 {
+		t.Fatalf("expected %d currencies, got %d", len(data.SampleRates)+1, len(resp.GetCurrencies()))
+	}
+
+	for _, ci := range resp.GetCurrencies() {
+		if ci.GetCode() == "JPY" && (ci.GetNumericCode() != "392" || ci.GetMinorUnits() != 0) {
+			t.Fatalf("unexpected details for JPY %v", ci)
+		}
+
+		// HRK is no longer published
+		if ci.GetCode() == "HRK" {
+			t.Fatal("expected only currencies with a rate")
+		}
+	}
+}
+
+func TestGetRateWithCurrencyCodes(t *testing.T) {
+	c := newTestCurrency(t)
+
+	resp, err := c.GetRate(context.Background(), &protos.RateRequest{BaseCode: "eur", DestinationCode: "ZAR"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.GetRate() != data.SampleRates["ZAR"] || resp.GetDestination() != protos.Currencies_ZAR || resp.GetDestinationCode() != "ZAR" {
+		t.Fatalf("unexpected response %v", resp)
+	}
+
+	// codes take precedence over the enum
+	_, err = c.GetRate(context.Background(), &protos.RateRequest{Base: protos.Currencies_USD, BaseCode: "XXX"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument for unknown currency, got %v", err)
+	}
+}
+
+func TestRateResponseProvenance(t *testing.T) {
+	c := newTestCurrency(t)
+
+	resp, err := c.GetRate(context.Background(), &protos.RateRequest{BaseCode: "GBP", DestinationCode: "USD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.GetSource() != "memory" || resp.GetStale() || !resp.GetAsOf().AsTime().Equal(c.rates.Fetched()) {
+		t.Fatalf("expected provenance of the provider rates, got %v", resp)
+	}
+
+	// streamed rates carry the provenance of the simulated rates
+	s := newTestStream()
+	done := make(chan error)
+	go func() { done <- c.SubscribeRates(s) }()
+
+	s.send(subscribe(protos.Currencies_GBP, protos.Currencies_USD))
+	c.rates.Tick(data.NewSimulator(data.Simulation{Seed: 1, MaxDrift: 0.01}))
+	c.broadcast()
+	close(s.recv)
+	<-done
+
+	m := s.messages()
+	if len(m) == 0 || m[len(m)-1].GetRateResponse().GetSource() != data.SourceSimulation {
+		t.Fatalf("expected simulated rate, got %v", m)
+	}
+}
+
+func TestSubscribeRatesTreatsCodesAndEnumsAlike(t *testing.T) {
+	c := newTestCurrency(t)
+	s := newTestStream()
+
+	done := make(chan error)
+	go func() { done <- c.SubscribeRates(s) }()
+
+	s.send(subscribe(protos.Currencies_EUR, protos.Currencies_USD))
+	s.send(&protos.RateRequest{BaseCode: "EUR", DestinationCode: "USD"})
+	close(s.recv)
+	<-done
+
+	m := s.messages()
+	if len(m) != 1 || codes.Code(m[0].GetError().GetCode()) != codes.AlreadyExists {
+		t.Fatalf("expected subscription to already exist, got %v", m)
+	}
+}
+
+func TestSimulatedStreamIsReproducible(t *testing.T) {
+	stream := func() []float64 {
+		r, err := data.NewRates(hclog.NewNullLogger(), data.NewMemory(data.SampleRates))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// updates are broadcast by the test rather than handleUpdates
+		c := &Currency{rates: r, log: hclog.NewNullLogger(), subscriptions: newSubscriptions(Queue{}, nil, hclog.NewNullLogger())}
+		sim := data.NewSimulator(data.Simulation{Seed: 7, MaxDrift: 0.01, MeanReversion: 0.05})
+
+		s := newTestStream()
+		go c.SubscribeRates(s)
+		defer close(s.recv)
+
+		s.send(subscribe(protos.Currencies_GBP, protos.Currencies_JPY))
+
+		for i := 0; i < 20; i++ {
+			c.rates.Tick(sim)
+			c.broadcast()
+		}
+
+		rates := []float64{}
+		for _, m := range s.wait(t, 20) {
+			rates = append(rates, m.GetRateResponse().GetRate())
+		}
+
+		return rates
+	}
+
+	a, b := stream(), stream()
+	if len(a) != 20 || len(b) != 20 {
+		t.Fatalf("expected 20 updates, got %d and %d", len(a), len(b))
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("expected update %d to be the same, got %f and %f", i, a[i], b[i])
+		}
+	}
+}
+
+func TestDrainEndsStreams(t *testing.T) {
+	c := newTestCurrency(t)
+	s := newTestStream()
+	defer close(s.recv)
+
+	done := make(chan error)
+	go func() { done <- c.SubscribeRates(s) }()
+
+	s.send(subscribe(protos.Currencies_EUR, protos.Currencies_USD))
+	c.Drain()
+
+	err := <-done
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected stream to end with unavailable, got %v", err)
+	}
+
+	// the client receives a final message telling it to reconnect
+	m := s.messages()
+	if len(m) != 1 || codes.Code(m[0].GetError().GetCode()) != codes.Unavailable {
+		t.Fatalf("expected a final unavailable message, got %v", m)
+	}
+
+	if n := len(c.subscriptions.Subscribers()); n != 0 {
+		t.Fatalf("expected subscriptions to be removed, got %d", n)
+	}
+
+	// new streams are rejected
+	err = c.SubscribeRates(newTestStream())
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected new stream to be rejected, got %v", err)
+	}
+}
+
+func TestGetCandles(t *testing.T) {
+	cs, err := data.NewCandles(data.CandleConfig{Pairs: []string{"EUR/USD"}, Resolutions: []data.Resolution{data.Minute}, Size: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := data.NewRates(hclog.NewNullLogger(), data.NewMemory(data.SampleRates), data.WithCandles(cs))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewCurrency(r, Queue{}, nil, hclog.NewNullLogger())
+
+	resp, err := c.GetCandles(context.Background(), &protos.CandlesRequest{Pair: "usd/eur", Resolution: "1m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.GetPair() != "USD/EUR" || len(resp.GetCandles()) != 1 || resp.GetCandles()[0].GetClose() != 1/data.SampleRates["USD"] {
+		t.Fatalf("expected a candle for the inverted pair, got %v", resp)
+	}
+
+	tests := map[string]struct {
+		req  *protos.CandlesRequest
+		code codes.Code
+	}{
+		"resolution":           {&protos.CandlesRequest{Pair: "EUR/USD", Resolution: "5m"}, codes.InvalidArgument},
+		"pair":                 {&protos.CandlesRequest{Pair: "EURUSD", Resolution: "1m"}, codes.InvalidArgument},
+		"range":                {&protos.CandlesRequest{Pair: "EUR/USD", Resolution: "1m", From: timestamppb.Now(), To: timestamppb.New(time.Now().Add(-time.Hour))}, codes.InvalidArgument},
+		"untracked pair":       {&protos.CandlesRequest{Pair: "EUR/GBP", Resolution: "1m"}, codes.NotFound},
+		"untracked resolution": {&protos.CandlesRequest{Pair: "EUR/USD", Resolution: "1d"}, codes.NotFound},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := c.GetCandles(context.Background(), tc.req)
+			if status.Code(err) != tc.code {
+				t.Fatalf("expected %s, got %v", tc.code, err)
+			}
+		})
+	}
+}
+
+func TestReplayIsStreamedToSubscribers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recording.ndjson")
+
+	// record a simulated stream, then replay it a thousand times faster
+	rec, err := data.NewRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorded, err := data.NewRates(hclog.NewNullLogger(), data.NewMemory(data.SampleRates), data.WithRecorder(rec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sim := data.NewSimulator(data.Simulation{Seed: 3, MaxDrift: 0.01})
+	for i := 0; i < 20; i++ {
+		recorded.Tick(sim)
+	}
+	rec.Close()
+
+	records, err := data.LoadRecording(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rp, err := data.NewReplay(path, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := data.NewRates(hclog.NewNullLogger(), rp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the replay is started with the service, before any client has connected
+	c := NewCurrency(r, Queue{}, nil, hclog.NewNullLogger())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c.Replay(ctx, rp)
+
+	// the replay would have finished long before the client connects
+	time.Sleep(50 * time.Millisecond)
+
+	s := newTestStream()
+	go c.SubscribeRates(s)
+	defer close(s.recv)
+
+	s.send(subscribe(protos.Currencies_GBP, protos.Currencies_JPY))
+
+	// the fetched rates and every tick are received in the recorded order
+	m := s.wait(t, len(records))
+	if len(m) != len(records) {
+		t.Fatalf("expected %d updates, got %d", len(records), len(m))
+	}
+
+	for i, rec := range records {
+		rr := m[i].GetRateResponse()
+		if want := rec.Rates["JPY"] / rec.Rates["GBP"]; rr.GetRate() != want {
+			t.Fatalf("expected update %d to be %f, got %f", i, want, rr.GetRate())
+		}
+
+		if rr.GetSource() != data.SourceReplay || !rr.GetAsOf().AsTime().Equal(rec.Time) {
+			t.Fatalf("expected update %d from the replay at %s, got %v", i, rec.Time, rr)
+		}
+	}
+}
+
+// panicStream is a SubscribeRates stream which panics when a request is read
+type panicStream struct {
+	*testStream
+}
+
+func (p panicStream) Recv() (*protos.RateRequest, error) {
+	panic("unable to read request")
+}
+
+func TestSubscribeRatesRecoversFromPanic(t *testing.T) {
+	c := newTestCurrency(t)
+
+	err := c.SubscribeRates(panicStream{newTestStream()})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("expected the panic to end the stream with internal, got %v", err)
+	}
+
+	if n := len(c.subscriptions.Subscribers()); n != 0 {
+		t.Fatalf("expected subscriptions to be removed, got %d", n)
+	}
+}
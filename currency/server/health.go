@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hnsia/go-nic/currency/data"
+	protos "github.com/hnsia/go-nic/currency/protos/currency/currency"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Health is an implementation of the standard gRPC health service which reports
+// the currency service as serving only while it has trustworthy rates
+type Health struct {
+	*health.Server
+	rates  *data.ExchangeRates
+	maxAge time.Duration
+	log    hclog.Logger
+	now    func() time.Time
+
+	mu     sync.Mutex
+	status healthpb.HealthCheckResponse_ServingStatus
+}
+
+// NewHealth creates a new health service, the service reports NOT_SERVING until
+// rates have been loaded and whenever the rates were fetched from the provider
+// longer than maxAge ago. A maxAge of 0 disables the age check
+func NewHealth(r *data.ExchangeRates, maxAge time.Duration, l hclog.Logger) *Health {
+	h := &Health{
+		Server: health.NewServer(),
+		rates:  r,
+		maxAge: maxAge,
+		log:    l,
+		now:    time.Now,
+		status: healthpb.HealthCheckResponse_UNKNOWN,
+	}
+
+	h.Update()
+
+	return h
+}
+
+// Update sets the serving status from the age of the current rates
+func (h *Health) Update() {
+	status := healthpb.HealthCheckResponse_SERVING
+
+	fetched := h.rates.Fetched()
+	age := h.now().Sub(fetched)
+
+	switch {
+	case fetched.IsZero():
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	case h.maxAge > 0 && age > h.maxAge:
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if status == h.status {
+		return
+	}
+
+	h.log.Info("Health status changed", "status", status, "fetched", fetched, "age", age)
+	h.status = status
+
+	// set the status for the server as a whole and the currency service
+	h.SetServingStatus("", status)
+	h.SetServingStatus(protos.Currency_ServiceDesc.ServiceName, status)
+}
+
+// Monitor updates the serving status at the given interval until the context is cancelled
+func (h *Health) Monitor(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				h.Update()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hnsia/go-nic/currency/data"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestHealthReportsStaleRates(t *testing.T) {
+	r, err := data.NewRates(hclog.NewNullLogger(), data.NewMemory(data.SampleRates))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewHealth(r, time.Hour, hclog.NewNullLogger())
+
+	check := func(expected healthpb.HealthCheckResponse_ServingStatus) {
+		t.Helper()
+
+		for _, svc := range []string{"", "Currency"} {
+			resp, err := h.Check(context.Background(), &healthpb.HealthCheckRequest{Service: svc})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.GetStatus() != expected {
+				t.Fatalf("expected service %q to be %s, got %s", svc, expected, resp.GetStatus())
+			}
+		}
+	}
+
+	check(healthpb.HealthCheckResponse_SERVING)
+
+	// rates are older than the max age
+	h.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	h.Update()
+	check(healthpb.HealthCheckResponse_NOT_SERVING)
+
+	h.now = time.Now
+	h.Update()
+	check(healthpb.HealthCheckResponse_SERVING)
+}
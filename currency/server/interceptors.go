@@ -0,0 +1,174 @@
+package server
+
+import (
+	"context"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Interceptors logs, recovers from panics and counts the requests handled by
+// the gRPC server, they are registered with the server options returned by Options
+type Interceptors struct {
+	log hclog.Logger
+
+	// mu guards the fields below
+	mu       sync.Mutex
+	requests map[RequestKey]uint64 // completed requests
+	streams  map[string]int64      // open streams by method
+}
+
+// RequestKey identifies the method and status code of completed requests
+type RequestKey struct {
+	Method string
+	Code   codes.Code
+}
+
+// NewInterceptors creates new interceptors using the given logger
+func NewInterceptors(l hclog.Logger) *Interceptors {
+	return &Interceptors{log: l, requests: map[RequestKey]uint64{}, streams: map[string]int64{}}
+}
+
+// Options returns the server options which register the interceptors
+func (i *Interceptors) Options() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(i.Unary),
+		grpc.ChainStreamInterceptor(i.Stream),
+	}
+}
+
+// Unary is a unary server interceptor
+func (i *Interceptors) Unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	start := time.Now()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = i.recovered(info.FullMethod, r)
+		}
+
+		i.done(info.FullMethod, err, start)
+	}()
+
+	return handler(ctx, req)
+}
+
+// Stream is a stream server interceptor, it also tracks the number of open streams
+func (i *Interceptors) Stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	start := time.Now()
+	i.addStream(info.FullMethod, 1)
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = i.recovered(info.FullMethod, r)
+		}
+
+		i.addStream(info.FullMethod, -1)
+		i.done(info.FullMethod, err, start)
+	}()
+
+	return handler(srv, ss)
+}
+
+// Requests returns a copy of the completed request counts
+func (i *Interceptors) Requests() map[RequestKey]uint64 {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	r := make(map[RequestKey]uint64, len(i.requests))
+	for k, v := range i.requests {
+		r[k] = v
+	}
+
+	return r
+}
+
+// Streams returns the number of open streams for the method
+func (i *Interceptors) Streams(method string) int64 {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.streams[method]
+}
+
+// StreamMethods returns the sorted names of the methods which have had streams
+func (i *Interceptors) StreamMethods() []string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	m := make([]string, 0, len(i.streams))
+	for k := range i.streams {
+		m = append(m, k)
+	}
+	sort.Strings(m)
+
+	return m
+}
+
+var (
+	requestsDesc = prometheus.NewDesc(
+		"currency_grpc_requests_total",
+		"Number of completed gRPC requests by method and status code",
+		[]string{"method", "code"}, nil,
+	)
+	streamsDesc = prometheus.NewDesc(
+		"currency_grpc_streams_open",
+		"Number of open gRPC streams by method",
+		[]string{"method"}, nil,
+	)
+)
+
+// Describe implements prometheus.Collector
+func (i *Interceptors) Describe(ch chan<- *prometheus.Desc) {
+	ch <- requestsDesc
+	ch <- streamsDesc
+}
+
+// Collect implements prometheus.Collector, the request and stream counts
+// are exported as metrics
+func (i *Interceptors) Collect(ch chan<- prometheus.Metric) {
+	for k, v := range i.Requests() {
+		ch <- prometheus.MustNewConstMetric(requestsDesc, prometheus.CounterValue, float64(v), k.Method, k.Code.String())
+	}
+
+	for _, m := range i.StreamMethods() {
+		ch <- prometheus.MustNewConstMetric(streamsDesc, prometheus.GaugeValue, float64(i.Streams(m)), m)
+	}
+}
+
+// recovered logs the panic and returns an Internal error for the client
+func (i *Interceptors) recovered(method string, r any) error {
+	i.log.Error("Recovered from panic", "method", method, "panic", r, "stack", string(debug.Stack()))
+
+	return status.Errorf(codes.Internal, "Internal error handling %s", method)
+}
+
+// done logs and counts a completed request
+func (i *Interceptors) done(method string, err error, start time.Time) {
+	code := status.Code(err)
+	d := time.Since(start)
+
+	if err != nil && code != codes.Canceled {
+		i.log.Error("Handled request", "method", method, "code", code, "duration", d, "error", err)
+	} else {
+		i.log.Info("Handled request", "method", method, "code", code, "duration", d)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.requests[RequestKey{method, code}]++
+}
+
+func (i *Interceptors) addStream(method string, n int64) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.streams[method] += n
+}
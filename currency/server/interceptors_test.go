@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryInterceptorRecoversFromPanic(t *testing.T) {
+	i := NewInterceptors(hclog.NewNullLogger())
+	info := &grpc.UnaryServerInfo{FullMethod: "/Currency/GetRate"}
+
+	_, err := i.Unary(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+		panic("boom")
+	})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("expected internal error, got %v", err)
+	}
+
+	_, err = i.Unary(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := i.Requests()
+	if r[RequestKey{"/Currency/GetRate", codes.Internal}] != 1 || r[RequestKey{"/Currency/GetRate", codes.OK}] != 1 {
+		t.Fatalf("unexpected request counts %v", r)
+	}
+}
+
+func TestStreamInterceptorTracksOpenStreams(t *testing.T) {
+	i := NewInterceptors(hclog.NewNullLogger())
+	info := &grpc.StreamServerInfo{FullMethod: "/Currency/SubscribeRates"}
+
+	err := i.Stream(nil, nil, info, func(srv any, ss grpc.ServerStream) error {
+		if n := i.Streams(info.FullMethod); n != 1 {
+			t.Fatalf("expected 1 open stream, got %d", n)
+		}
+
+		panic("boom")
+	})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("expected internal error, got %v", err)
+	}
+
+	if n := i.Streams(info.FullMethod); n != 0 {
+		t.Fatalf("expected no open streams, got %d", n)
+	}
+}
+
+func TestInterceptorsCollectMetrics(t *testing.T) {
+	i := NewInterceptors(hclog.NewNullLogger())
+	info := &grpc.UnaryServerInfo{FullMethod: "/Currency/GetRate"}
+
+	i.Unary(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+		return nil, status.Error(codes.NotFound, "not found")
+	})
+
+	expected := `
+# HELP currency_grpc_requests_total Number of completed gRPC requests by method and status code
+# TYPE currency_grpc_requests_total counter
+currency_grpc_requests_total{code="NotFound",method="/Currency/GetRate"} 1
+`
+
+	err := testutil.CollectAndCompare(i, strings.NewReader(expected), "currency_grpc_requests_total")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"fmt"
+	"math/big"
+
+	protos "github.com/hnsia/go-nic/currency/protos/currency/currency"
+)
+
+const nanosPerUnit = 1_000_000_000
+
+// moneyToRat returns the exact value of the Money message
+func moneyToRat(m *protos.Money) (*big.Rat, error) {
+	if m == nil {
+		return nil, fmt.Errorf("amount is required")
+	}
+
+	units, nanos := m.GetUnits(), m.GetNanos()
+
+	if nanos <= -nanosPerUnit || nanos >= nanosPerUnit {
+		return nil, fmt.Errorf("nanos %d must be between -999999999 and 999999999", nanos)
+	}
+
+	if (units > 0 && nanos < 0) || (units < 0 && nanos > 0) {
+		return nil, fmt.Errorf("units %d and nanos %d must have the same sign", units, nanos)
+	}
+
+	r := new(big.Rat).SetFrac(big.NewInt(int64(nanos)), big.NewInt(nanosPerUnit))
+	return r.Add(r, new(big.Rat).SetInt64(units)), nil
+}
+
+// ratToMoney returns the Money message for the value, digits smaller
+// than nanos are truncated
+func ratToMoney(r *big.Rat) (*protos.Money, error) {
+	// the total number of nanos
+	n := new(big.Int).Mul(r.Num(), big.NewInt(nanosPerUnit))
+	n.Quo(n, r.Denom())
+
+	units, nanos := new(big.Int).QuoRem(n, big.NewInt(nanosPerUnit), new(big.Int))
+	if !units.IsInt64() {
+		return nil, fmt.Errorf("amount %s is too large", r.FloatString(0))
+	}
+
+	return &protos.Money{Units: units.Int64(), Nanos: int32(nanos.Int64())}, nil
+}
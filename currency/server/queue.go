@@ -0,0 +1,176 @@
+package server
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hnsia/go-nic/currency/metrics"
+	protos "github.com/hnsia/go-nic/currency/protos/currency/currency"
+)
+
+// DefaultQueueSize is the number of messages which can wait to be sent to a subscriber
+const DefaultQueueSize = 64
+
+// OverflowPolicy decides what happens to a message sent to a subscriber whose queue is full
+type OverflowPolicy int
+
+const (
+	// DropOldest drops the oldest queued message to make room for the new message
+	DropOldest OverflowPolicy = iota
+	// Coalesce replaces a queued update for the same rate with the latest update,
+	// the oldest message is dropped when no update for the rate is queued
+	Coalesce
+	// Disconnect ends the stream of a subscriber which does not keep up
+	Disconnect
+)
+
+var policyNames = map[OverflowPolicy]string{
+	DropOldest: "drop_oldest",
+	Coalesce:   "coalesce",
+	Disconnect: "disconnect",
+}
+
+func (p OverflowPolicy) String() string {
+	if n, ok := policyNames[p]; ok {
+		return n
+	}
+
+	return fmt.Sprintf("OverflowPolicy(%d)", int(p))
+}
+
+// ParseOverflowPolicy returns the policy for the name [drop_oldest, coalesce, disconnect]
+func ParseOverflowPolicy(name string) (OverflowPolicy, error) {
+	for p, n := range policyNames {
+		if n == name {
+			return p, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown overflow policy %q, expected drop_oldest, coalesce or disconnect", name)
+}
+
+// Queue configures the messages waiting to be sent to each subscriber, the zero
+// value uses DefaultQueueSize and the DropOldest policy
+type Queue struct {
+	// Size is the number of messages which can wait to be sent
+	Size int
+	// Overflow decides what happens when a message is sent to a full queue
+	Overflow OverflowPolicy
+}
+
+// queue is a bounded queue of messages for a single subscriber, messages are
+// added by the broadcaster and request handler and removed by the goroutine
+// which writes to the stream
+type queue struct {
+	size    int
+	policy  OverflowPolicy
+	metrics *metrics.Metrics
+
+	overflow chan struct{} // closed when the queue overflows with the Disconnect policy
+
+	// mu guards the fields below, ready is signalled when they change
+	mu     sync.Mutex
+	ready  *sync.Cond
+	items  []*protos.StreamingRateResponse
+	closed bool
+}
+
+func newQueue(c Queue, m *metrics.Metrics) *queue {
+	if c.Size <= 0 {
+		c.Size = DefaultQueueSize
+	}
+
+	q := &queue{size: c.Size, policy: c.Overflow, metrics: m, overflow: make(chan struct{})}
+	q.ready = sync.NewCond(&q.mu)
+
+	return q
+}
+
+// push adds the message to the queue applying the overflow policy, it never
+// blocks. Messages pushed after the queue is closed are ignored
+func (q *queue) push(m *protos.StreamingRateResponse) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return
+	}
+
+	// the client only needs the latest value of a rate
+	if q.policy == Coalesce && m.GetRateResponse() != nil {
+		for i, v := range q.items {
+			if sameResponse(v.GetRateResponse(), m.GetRateResponse()) {
+				q.items[i] = m
+				q.metrics.Dropped(q.policy.String(), 1)
+				return
+			}
+		}
+	}
+
+	if len(q.items) >= q.size {
+		if q.policy == Disconnect {
+			q.metrics.Dropped(q.policy.String(), len(q.items)+1)
+			q.metrics.SlowDisconnect()
+			q.closeLocked(false)
+			close(q.overflow)
+			return
+		}
+
+		q.items[0] = nil
+		q.items = q.items[1:]
+		q.metrics.Dropped(q.policy.String(), 1)
+	}
+
+	q.items = append(q.items, m)
+	q.ready.Signal()
+}
+
+// pop removes the oldest message from the queue, it blocks until a message is
+// available. False is returned once the queue is closed and empty
+func (q *queue) pop() (*protos.StreamingRateResponse, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for len(q.items) == 0 && !q.closed {
+		q.ready.Wait()
+	}
+
+	if len(q.items) == 0 {
+		return nil, false
+	}
+
+	m := q.items[0]
+	q.items[0] = nil
+	q.items = q.items[1:]
+
+	return m, true
+}
+
+// close stops the queue accepting messages, when flush is false the queued
+// messages are discarded rather than sent
+func (q *queue) close(flush bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.closeLocked(flush)
+}
+
+func (q *queue) closeLocked(flush bool) {
+	q.closed = true
+	if !flush {
+		q.items = nil
+	}
+
+	q.ready.Broadcast()
+}
+
+// overflowed returns a channel which is closed when the subscriber has been
+// disconnected by the Disconnect policy
+func (q *queue) overflowed() <-chan struct{} {
+	return q.overflow
+}
+
+// sameResponse returns true when both responses are for the same currencies and spread profile
+func sameResponse(a, b *protos.RateResponse) bool {
+	return a != nil && a.GetBaseCode() == b.GetBaseCode() && a.GetDestinationCode() == b.GetDestinationCode() && a.GetProfile() == b.GetProfile()
+}
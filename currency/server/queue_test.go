@@ -0,0 +1,163 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hnsia/go-nic/currency/data"
+	protos "github.com/hnsia/go-nic/currency/protos/currency/currency"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func rateMessage(dest string, rate float64) *protos.StreamingRateResponse {
+	return &protos.StreamingRateResponse{
+		Message: &protos.StreamingRateResponse_RateResponse{
+			RateResponse: &protos.RateResponse{BaseCode: "EUR", DestinationCode: dest, Rate: rate},
+		},
+	}
+}
+
+// drain closes the queue and returns the destinations of the queued messages
+func drain(q *queue) []string {
+	q.close(true)
+
+	got := []string{}
+	for {
+		m, ok := q.pop()
+		if !ok {
+			return got
+		}
+
+		got = append(got, m.GetRateResponse().GetDestinationCode())
+	}
+}
+
+func TestQueueOverflowPolicies(t *testing.T) {
+	tests := []struct {
+		policy OverflowPolicy
+		push   []string
+		queued []string
+	}{
+		{DropOldest, []string{"USD", "GBP", "USD", "JPY"}, []string{"GBP", "USD", "JPY"}},
+		{Coalesce, []string{"USD", "GBP", "USD", "JPY"}, []string{"USD", "GBP", "JPY"}},
+		{Coalesce, []string{"USD", "GBP", "JPY", "CHF"}, []string{"GBP", "JPY", "CHF"}},
+		{Disconnect, []string{"USD", "GBP", "USD"}, []string{"USD", "GBP", "USD"}},
+		{Disconnect, []string{"USD", "GBP", "USD", "JPY"}, []string{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.policy.String(), func(t *testing.T) {
+			q := newQueue(Queue{Size: 3, Overflow: tc.policy}, nil)
+			for i, d := range tc.push {
+				q.push(rateMessage(d, float64(i)))
+			}
+
+			got := drain(q)
+			if len(got) != len(tc.queued) {
+				t.Fatalf("expected %v, got %v", tc.queued, got)
+			}
+
+			for i := range got {
+				if got[i] != tc.queued[i] {
+					t.Fatalf("expected %v, got %v", tc.queued, got)
+				}
+			}
+
+			select {
+			case <-q.overflowed():
+				if len(tc.push) <= 3 {
+					t.Fatal("expected the queue not to overflow")
+				}
+			default:
+				if tc.policy == Disconnect && len(tc.push) > 3 {
+					t.Fatal("expected the queue to overflow")
+				}
+			}
+		})
+	}
+}
+
+func TestCoalesceKeepsLatestRate(t *testing.T) {
+	q := newQueue(Queue{Size: 3, Overflow: Coalesce}, nil)
+	q.push(rateMessage("USD", 1))
+	q.push(rateMessage("USD", 2))
+	q.close(true)
+
+	m, _ := q.pop()
+	if m.GetRateResponse().GetRate() != 2 {
+		t.Fatalf("expected the latest rate, got %v", m)
+	}
+
+	if _, ok := q.pop(); ok {
+		t.Fatal("expected a single message")
+	}
+}
+
+func TestParseOverflowPolicy(t *testing.T) {
+	for _, p := range []OverflowPolicy{DropOldest, Coalesce, Disconnect} {
+		got, err := ParseOverflowPolicy(p.String())
+		if err != nil || got != p {
+			t.Fatalf("expected %s, got %s %v", p, got, err)
+		}
+	}
+
+	_, err := ParseOverflowPolicy("block")
+	if err == nil {
+		t.Fatal("expected error for unknown policy")
+	}
+}
+
+// blockedStream is a stream whose client does not read, Send blocks until unblock is closed
+type blockedStream struct {
+	*testStream
+	unblock chan struct{}
+}
+
+func (b *blockedStream) Send(m *protos.StreamingRateResponse) error {
+	<-b.unblock
+	return b.testStream.Send(m)
+}
+
+func TestSlowClientDoesNotStallOthers(t *testing.T) {
+	r, err := data.NewRates(hclog.NewNullLogger(), data.NewMemory(data.SampleRates))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Currency{rates: r, log: hclog.NewNullLogger(), subscriptions: newSubscriptions(Queue{Size: 2, Overflow: Disconnect}, nil, hclog.NewNullLogger())}
+
+	slow := &blockedStream{testStream: newTestStream(), unblock: make(chan struct{})}
+	defer close(slow.unblock)
+	defer close(slow.recv)
+
+	fast := newTestStream()
+	defer close(fast.recv)
+
+	slowDone := make(chan error, 1)
+	go func() { slowDone <- c.SubscribeRates(slow) }()
+	go c.SubscribeRates(fast)
+
+	slow.send(subscribe(protos.Currencies_EUR, protos.Currencies_USD))
+	fast.send(subscribe(protos.Currencies_EUR, protos.Currencies_USD))
+
+	// the slow client holds at most one message in Send and two in
+	// the queue so the fourth message overflows the queue
+	sim := data.NewSimulator(data.Simulation{Seed: 1, MaxDrift: 0.01})
+	for i := 0; i < 4; i++ {
+		r.Tick(sim)
+		c.broadcast()
+
+		// wait for each message so the fast client never overflows
+		fast.wait(t, i+1)
+	}
+
+	err = <-slowDone
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected the slow client to be disconnected, got %v", err)
+	}
+
+	if n := len(c.subscriptions.Subscribers()); n != 1 {
+		t.Fatalf("expected only the fast client to remain, got %d", n)
+	}
+}
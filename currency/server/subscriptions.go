@@ -0,0 +1,219 @@
+package server
+
+import (
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hnsia/go-nic/currency/metrics"
+	protos "github.com/hnsia/go-nic/currency/protos/currency/currency"
+)
+
+// subscription is a subscribed rate and the alert which decides when updates are sent
+type subscription struct {
+	request *protos.RateRequest
+	alert   *alert
+}
+
+// subscriber is a client stream and the rates it has subscribed to. Messages
+// are queued and written to the stream by a single goroutine so a slow client
+// does not hold up the broadcast to other clients
+type subscriber struct {
+	stream  protos.Currency_SubscribeRatesServer
+	log     hclog.Logger
+	metrics *metrics.Metrics
+	queue   *queue
+	written chan struct{} // closed when the writer has stopped
+
+	// mu guards the fields below
+	mu       sync.Mutex
+	requests []*subscription
+	closed   bool // set when the stream has been removed from the registry
+}
+
+func newSubscriber(stream protos.Currency_SubscribeRatesServer, q Queue, m *metrics.Metrics, l hclog.Logger) *subscriber {
+	s := &subscriber{stream: stream, log: l, metrics: m, queue: newQueue(q, m), written: make(chan struct{})}
+	go s.write()
+
+	return s
+}
+
+// Send queues the message for the client stream, it does not wait for the
+// message to be sent
+func (s *subscriber) Send(m *protos.StreamingRateResponse) {
+	s.queue.push(m)
+}
+
+// write sends the queued messages to the stream until the queue is closed,
+// gRPC does not allow concurrent calls to Send so this is the only sender
+func (s *subscriber) write() {
+	defer close(s.written)
+
+	for {
+		m, ok := s.queue.pop()
+		if !ok {
+			return
+		}
+
+		err := s.stream.Send(m)
+		if err != nil {
+			s.metrics.SendFailure()
+			s.log.Error("Unable to send message to client", "error", err)
+		}
+	}
+}
+
+// Requests returns a copy of the subscriptions for the client
+func (s *subscriber) Requests() []*subscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*subscription{}, s.requests...)
+}
+
+// Add adds the request to the subscriptions for the client, updates are sent
+// when the alert fires. False is returned when the client is already subscribed
+// to the rate, requests received after the stream was removed from the
+// registry are ignored
+func (s *subscriber) Add(rr *protos.RateRequest, a *alert) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, v := range s.requests {
+		if sameRate(v.request, rr) {
+			return false
+		}
+	}
+
+	if !s.closed {
+		s.requests = append(s.requests, &subscription{request: rr, alert: a})
+		s.metrics.AddSubscriptions(1)
+	}
+
+	return true
+}
+
+// Remove removes the request from the subscriptions for the client, false is
+// returned when the client is not subscribed to the rate
+func (s *subscriber) Remove(rr *protos.RateRequest) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, v := range s.requests {
+		if sameRate(v.request, rr) {
+			v.alert.stop()
+			s.requests = append(s.requests[:i], s.requests[i+1:]...)
+			s.metrics.AddSubscriptions(-1)
+			return true
+		}
+	}
+
+	return false
+}
+
+// close removes all of the subscriptions for the client and waits for the
+// queued messages to be sent. It does not wait for a client which was
+// disconnected for being too slow or whose stream has ended, the stream
+// is closed when the handler returns which fails any pending send
+func (s *subscriber) close() {
+	s.mu.Lock()
+	s.metrics.AddSubscriptions(-len(s.requests))
+	for _, v := range s.requests {
+		v.alert.stop()
+	}
+	s.requests = nil
+	s.closed = true
+	s.mu.Unlock()
+
+	s.queue.close(true)
+
+	select {
+	case <-s.written:
+	case <-s.queue.overflowed():
+	case <-s.stream.Context().Done():
+	}
+}
+
+// subscriptions is a registry of the client streams subscribed to rate updates,
+// it is safe for concurrent use
+type subscriptions struct {
+	queue   Queue
+	metrics *metrics.Metrics
+	log     hclog.Logger
+
+	subscribed     chan struct{} // closed when the first rate is subscribed
+	subscribedOnce sync.Once
+
+	mu          sync.RWMutex
+	subscribers map[protos.Currency_SubscribeRatesServer]*subscriber
+}
+
+func newSubscriptions(q Queue, m *metrics.Metrics, l hclog.Logger) *subscriptions {
+	return &subscriptions{
+		queue:       q,
+		metrics:     m,
+		log:         l,
+		subscribed:  make(chan struct{}),
+		subscribers: map[protos.Currency_SubscribeRatesServer]*subscriber{},
+	}
+}
+
+// added records that a client has subscribed to a rate
+func (s *subscriptions) added() {
+	s.subscribedOnce.Do(func() {
+		close(s.subscribed)
+	})
+}
+
+// Get returns the subscriber for the given stream, creating it if it does not exist
+func (s *subscriptions) Get(stream protos.Currency_SubscribeRatesServer) *subscriber {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub, ok := s.subscribers[stream]
+	if !ok {
+		sub = newSubscriber(stream, s.queue, s.metrics, s.log)
+		s.subscribers[stream] = sub
+		s.metrics.Subscribers(len(s.subscribers))
+	}
+
+	return sub
+}
+
+// Remove removes the stream and all of its subscriptions, the messages queued
+// for the stream are sent before Remove returns
+func (s *subscriptions) Remove(stream protos.Currency_SubscribeRatesServer) {
+	s.mu.Lock()
+	sub, ok := s.subscribers[stream]
+	if ok {
+		delete(s.subscribers, stream)
+		s.metrics.Subscribers(len(s.subscribers))
+	}
+	s.mu.Unlock()
+
+	// the registry is not locked while the queue is flushed
+	if ok {
+		sub.close()
+	}
+}
+
+// Subscribers returns a copy of the current subscribers, the copy can be
+// used without holding the registry lock while messages are sent
+func (s *subscriptions) Subscribers() []*subscriber {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	subs := make([]*subscriber, 0, len(s.subscribers))
+	for _, sub := range s.subscribers {
+		subs = append(subs, sub)
+	}
+
+	return subs
+}
+
+// sameRate returns true when both requests are for the same currencies and spread profile
+func sameRate(a, b *protos.RateRequest) bool {
+	ab, ad := rateCodes(a)
+	bb, bd := rateCodes(b)
+
+	return ab == bb && ad == bd && a.GetProfile() == b.GetProfile()
+}
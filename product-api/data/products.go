@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"regexp"
+	"sync"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -77,98 +78,187 @@ func validateSKU(fl validator.FieldLevel) bool {
 
 type Products []*Product
 
+// Rate is the exchange rate used to convert the product prices
+type Rate struct {
+	Rate float64
+	// AsOf is the time the rate took effect
+	AsOf time.Time
+	// Source identifies where the currency service obtained the rate
+	Source string
+	// Stale is true when the currency service could not reach its provider
+	Stale bool
+}
+
+func newRate(rr *protos.RateResponse) *Rate {
+	return &Rate{
+		Rate:   rr.GetRate(),
+		AsOf:   rr.GetAsOf().AsTime(),
+		Source: rr.GetSource(),
+		Stale:  rr.GetStale(),
+	}
+}
+
+// the delays between attempts to reopen the rate stream after it has ended
+const (
+	minReconnectDelay = time.Second
+	maxReconnectDelay = 30 * time.Second
+)
+
 type ProductsDB struct {
 	currency protos.CurrencyClient
 	log      hclog.Logger
-	rates    map[string]float64
-	client   protos.Currency_SubscribeRatesClient
+
+	// mu guards the fields below
+	mu sync.Mutex
+	// rates caches the rates received while the stream is open
+	rates map[string]*Rate
+	// destinations are subscribed on every stream which is opened
+	destinations map[string]bool
+	// client is nil while the stream is being reopened
+	client protos.Currency_SubscribeRatesClient
 }
 
 func NewProductsDB(c protos.CurrencyClient, l hclog.Logger) *ProductsDB {
-	pb := &ProductsDB{c, l, make(map[string]float64), nil}
+	pb := &ProductsDB{
+		currency:     c,
+		log:          l,
+		rates:        make(map[string]*Rate),
+		destinations: make(map[string]bool),
+	}
 
 	go pb.handleUpdates()
 
 	return pb
 }
 
+// handleUpdates receives rate updates and reopens the stream with an
+// exponential backoff whenever it ends, e.g. when the currency service restarts
 func (p *ProductsDB) handleUpdates() {
+	delay := minReconnectDelay
+	for {
+		opened := time.Now()
+		err := p.receiveUpdates()
+
+		// no updates are received until the stream is reopened, the cached rates
+		// are dropped so the next request fetches a fresh rate
+		p.mu.Lock()
+		p.client = nil
+		clear(p.rates)
+		p.mu.Unlock()
+
+		// a stream which stayed open for a while restarts the backoff
+		if time.Since(opened) > maxReconnectDelay {
+			delay = minReconnectDelay
+		}
+
+		p.log.Error("Rate stream ended, reconnecting", "error", err, "delay", delay)
+		time.Sleep(delay)
+		delay = min(delay*2, maxReconnectDelay)
+	}
+}
+
+// receiveUpdates opens the rate stream, subscribes to every destination which
+// has been requested and updates the cached rates until the stream ends
+func (p *ProductsDB) receiveUpdates() error {
 	sub, err := p.currency.SubscribeRates(context.Background())
 	if err != nil {
-		p.log.Error("Unable to subscribe for rates", "error", err)
+		return err
 	}
 
+	p.mu.Lock()
 	p.client = sub
+	for dest := range p.destinations {
+		p.subscribe(dest)
+	}
+	p.mu.Unlock()
 
 	for {
 		rr, err := sub.Recv()
+		// the stream has ended, e.g. the currency service is shutting down
+		if err != nil {
+			return err
+		}
+
 		if grpcError := rr.GetError(); grpcError != nil {
 			p.log.Error("Error subscribing for rates", "error", grpcError)
 			continue
 		}
 
 		if resp := rr.GetRateResponse(); resp != nil {
-			p.log.Info("Received updated rate from server", "dest", resp.GetDestination().String())
-
-			if err != nil {
-				p.log.Error("Error receiving message", "error", err)
-				return
-			}
+			p.log.Info("Received updated rate from server", "dest", resp.GetDestinationCode())
 
-			p.rates[resp.Destination.String()] = resp.Rate
+			p.mu.Lock()
+			p.rates[resp.GetDestinationCode()] = newRate(resp)
+			p.mu.Unlock()
 		}
 	}
 }
 
+// subscribe sends a subscription for the destination on the open stream, the
+// caller must hold the lock
+func (p *ProductsDB) subscribe(destination string) {
+	err := p.client.Send(&protos.RateRequest{
+		BaseCode:        protos.Currencies_EUR.String(),
+		DestinationCode: destination,
+	})
+	if err != nil {
+		// the error is returned by Recv and the stream is reopened
+		p.log.Error("Unable to subscribe for rates", "dest", destination, "error", err)
+	}
+}
+
 func (p *Products) ToJSON(w io.Writer) error {
 	e := json.NewEncoder(w)
 	return e.Encode(p)
 }
 
-func (p *ProductsDB) GetProducts(currency string) (Products, error) {
+// GetProducts returns all products with their prices in the given currency along
+// with the rate used to convert them, the rate is nil when currency is empty
+func (p *ProductsDB) GetProducts(currency string) (Products, *Rate, error) {
 	if currency == "" {
-		return productList, nil
+		return productList, nil, nil
 	}
 
 	rate, err := p.getRate(currency)
 	if err != nil {
 		p.log.Error("Unable to get rate", "currency", currency, "error", err)
-		return nil, err
+		return nil, nil, err
 	}
 
 	pr := Products{}
 	for _, p := range productList {
 		np := *p
-		np.Price = np.Price * rate
+		np.Price = np.Price * rate.Rate
 		pr = append(pr, &np)
 	}
 
-	return pr, nil
+	return pr, rate, nil
 }
 
 // GetProductByID returns a single product which matches the id from the
 // database.
-// If a product is not found this function returns a ProductNotFound error
-func (p *ProductsDB) GetProductByID(id int, currency string) (*Product, error) {
+// If a product is not found this function returns a ProductNotFound error.
+// The rate used to convert the price is nil when currency is empty
+func (p *ProductsDB) GetProductByID(id int, currency string) (*Product, *Rate, error) {
 	product, i, err := findProduct(id)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if currency == "" {
-		return product, nil
+		return product, nil, nil
 	}
 
 	rate, err := p.getRate(currency)
 	if err != nil {
 		p.log.Error("Unable to get rate", "currency", currency, "error", err)
-		return nil, err
+		return nil, nil, err
 	}
 
 	np := *productList[i]
-	np.Price = np.Price * rate
+	np.Price = np.Price * rate.Rate
 
-	return &np, nil
+	return &np, rate, nil
 }
 
 func (pdb *ProductsDB) AddProduct(p *Product) {
@@ -215,14 +305,18 @@ func getNextID() int {
 	return lp.ID + 1
 }
 
-func (p *ProductsDB) getRate(destination string) (float64, error) {
+func (p *ProductsDB) getRate(destination string) (*Rate, error) {
 	// if cached, return
-	if r, ok := p.rates[destination]; ok {
+	p.mu.Lock()
+	r, ok := p.rates[destination]
+	p.mu.Unlock()
+	if ok {
 		return r, nil
 	}
+
 	rr := &protos.RateRequest{
-		Base:        protos.Currencies_EUR,
-		Destination: protos.Currencies(protos.Currencies_value[destination]),
+		BaseCode:        protos.Currencies_EUR.String(),
+		DestinationCode: destination,
 	}
 
 	// get initial rate
@@ -232,22 +326,36 @@ func (p *ProductsDB) getRate(destination string) (float64, error) {
 			md := s.Details()[0].(*protos.RateRequest)
 
 			if s.Code() == codes.InvalidArgument {
-				return -1, fmt.Errorf("unable to get rate from currency server, destination and base currencies can not be the same, base: %s, dest: %s", md.Base.String(), md.Destination.String())
+				return nil, fmt.Errorf("unable to get rate from currency server, %s, base: %s, dest: %s", s.Message(), md.GetBaseCode(), md.GetDestinationCode())
 			}
 
-			return -1, fmt.Errorf("unable to get rate from currency server, base: %s, dest: %s", md.Base.String(), md.Destination.String())
+			return nil, fmt.Errorf("unable to get rate from currency server, base: %s, dest: %s", md.GetBaseCode(), md.GetDestinationCode())
 		}
 
-		return -1, err
+		return nil, err
 	}
 
-	// update cache
-	p.rates[destination] = res.Rate
+	rate := newRate(res)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// the rate is only cached while updates are received for it, a destination
+	// requested while the stream is closed is subscribed when it reopens
+	if p.client != nil {
+		p.rates[destination] = rate
+	}
 
 	// subscribe for updates
-	p.client.Send(rr)
+	if !p.destinations[destination] {
+		p.destinations[destination] = true
+
+		if p.client != nil {
+			p.subscribe(destination)
+		}
+	}
 
-	return res.Rate, err
+	return rate, nil
 }
 
 var productList = []*Product{
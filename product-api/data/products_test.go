@@ -1,6 +1,17 @@
 package data
 
-import "testing"
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	protos "github.com/hnsia/go-nic/currency/protos/currency/currency"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
 
 func TestCheckVacalidation(t *testing.T) {
 	p := &Product{
@@ -15,3 +26,83 @@ func TestCheckVacalidation(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// fakeCurrency returns a rate which increases with every request and hands
+// every stream which is opened to the test
+type fakeCurrency struct {
+	protos.CurrencyClient
+
+	mu      sync.Mutex
+	rate    float64
+	streams chan *fakeStream
+}
+
+func (f *fakeCurrency) GetRate(ctx context.Context, rr *protos.RateRequest, opts ...grpc.CallOption) (*protos.RateResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.rate++
+	return &protos.RateResponse{BaseCode: rr.GetBaseCode(), DestinationCode: rr.GetDestinationCode(), Rate: f.rate}, nil
+}
+
+func (f *fakeCurrency) SubscribeRates(ctx context.Context, opts ...grpc.CallOption) (protos.Currency_SubscribeRatesClient, error) {
+	s := &fakeStream{sent: make(chan *protos.RateRequest, 10), end: make(chan error)}
+	f.streams <- s
+	return s, nil
+}
+
+type fakeStream struct {
+	protos.Currency_SubscribeRatesClient
+
+	sent chan *protos.RateRequest
+	end  chan error
+}
+
+func (s *fakeStream) Send(rr *protos.RateRequest) error {
+	s.sent <- rr
+	return nil
+}
+
+func (s *fakeStream) Recv() (*protos.StreamingRateResponse, error) {
+	return nil, <-s.end
+}
+
+func TestRatesResubscribeWhenStreamEnds(t *testing.T) {
+	fc := &fakeCurrency{streams: make(chan *fakeStream)}
+	pdb := NewProductsDB(fc, hclog.NewNullLogger())
+
+	first := <-fc.streams
+	rate, err := pdb.getRate("USD")
+	if err != nil || rate.Rate != 1 {
+		t.Fatalf("expected the first rate, got %v %v", rate, err)
+	}
+
+	if rr := <-first.sent; rr.GetDestinationCode() != "USD" {
+		t.Fatalf("expected a subscription for USD, got %v", rr)
+	}
+
+	// the currency service restarts
+	first.end <- status.Error(codes.Unavailable, "server is shutting down")
+
+	var second *fakeStream
+	select {
+	case second = <-fc.streams:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the stream to be reopened")
+	}
+
+	select {
+	case rr := <-second.sent:
+		if rr.GetDestinationCode() != "USD" {
+			t.Fatalf("expected the subscription for USD to be restored, got %v", rr)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the subscription to be restored")
+	}
+
+	// the rate cached from the ended stream is not served
+	rate, err = pdb.getRate("USD")
+	if err != nil || rate.Rate != 2 {
+		t.Fatalf("expected a fresh rate, got %v %v", rate, err)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/hashicorp/go-hclog"
@@ -85,13 +86,15 @@ func (p *Products) GetProducts(w http.ResponseWriter, r *http.Request) {
 	cur := r.URL.Query().Get("currency")
 
 	// fetch the products from the data store
-	lp, err := p.productDB.GetProducts(cur)
+	lp, rate, err := p.productDB.GetProducts(cur)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		data.ToJSON(&GenericError{Message: err.Error()}, w)
 		return
 	}
 
+	setRateHeaders(w, rate)
+
 	// serialize the list to JSON
 	err = data.ToJSON(lp, w)
 	if err != nil {
@@ -108,7 +111,7 @@ func (p *Products) ListSingle(w http.ResponseWriter, r *http.Request) {
 
 	p.l.Debug("Get record", "id", id)
 
-	prod, err := p.productDB.GetProductByID(id, cur)
+	prod, rate, err := p.productDB.GetProductByID(id, cur)
 
 	switch err {
 	case nil:
@@ -127,12 +130,27 @@ func (p *Products) ListSingle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setRateHeaders(w, rate)
+
 	err = data.ToJSON(prod, w)
 	if err != nil {
 		p.l.Error("error serializing product", "error", err)
 	}
 }
 
+// setRateHeaders adds the time and source of the exchange rate used
+// to convert the prices to the response, nothing is added when the
+// prices have not been converted
+func setRateHeaders(w http.ResponseWriter, r *data.Rate) {
+	if r == nil {
+		return
+	}
+
+	w.Header().Set("X-Rate-As-Of", r.AsOf.Format(time.RFC3339))
+	w.Header().Set("X-Rate-Source", r.Source)
+	w.Header().Set("X-Rate-Stale", strconv.FormatBool(r.Stale))
+}
+
 func (p *Products) AddProduct(w http.ResponseWriter, r *http.Request) {
 	prod := r.Context().Value(KeyProduct{}).(data.Product)
 
@@ -11,16 +11,44 @@ import (
 	gohandlers "github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
 	"github.com/hashicorp/go-hclog"
+	"github.com/hnsia/go-nic/currency/certs"
 	protos "github.com/hnsia/go-nic/currency/protos/currency/currency"
 	"github.com/hnsia/go-nic/product-api/data"
 	"github.com/hnsia/go-nic/product-api/handlers"
+	"github.com/nicholasjackson/env"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
+var currencyAddress = env.String("CURRENCY_ADDRESS", false, "localhost:9092", "Address of the currency service")
+var currencyTLS = env.Bool("CURRENCY_TLS", false, false, "Connect to the currency service using TLS")
+var currencyCA = env.String("CURRENCY_TLS_CA_FILE", false, "", "Path to a PEM encoded CA bundle used to verify the currency service, the system roots are used when empty")
+var currencyCert = env.String("CURRENCY_TLS_CERT_FILE", false, "", "Path to a PEM encoded client certificate presented to the currency service for mutual TLS")
+var currencyKey = env.String("CURRENCY_TLS_KEY_FILE", false, "", "Path to the PEM encoded private key for the client certificate")
+
 func main() {
 	l := hclog.Default()
 
-	conn, err := grpc.Dial("localhost:9092", grpc.WithInsecure())
+	err := env.Parse()
+	if err != nil {
+		l.Error("Unable to parse configuration", "error", err)
+		os.Exit(1)
+	}
+
+	var creds credentials.TransportCredentials = insecure.NewCredentials()
+	if *currencyTLS {
+		// the files are reloaded when they change
+		tf, err := certs.Load(*currencyCert, *currencyKey, *currencyCA, l)
+		if err != nil {
+			l.Error("Unable to load TLS files", "error", err)
+			os.Exit(1)
+		}
+
+		creds = tf.ClientCredentials()
+	}
+
+	conn, err := grpc.Dial(*currencyAddress, grpc.WithTransportCredentials(creds))
 	if err != nil {
 		panic(err)
 	}